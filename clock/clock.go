@@ -0,0 +1,96 @@
+// Package clock abstracts the passage of time, so that time-dependent code
+// can run against the real clock in production and against a FakeClock,
+// advanced explicitly, in tests.
+package clock
+
+import "time"
+
+// Clock tells the time and creates timers. Real returns the implementation
+// backed by package time; FakeClock is a deterministic one for tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+	// After returns a channel that receives the current time once d has
+	// passed, like time.After.
+	After(d time.Duration) <-chan time.Time
+	// AfterFunc calls f on its own goroutine once d has passed, like
+	// time.AfterFunc. The returned Timer's C method returns nil.
+	AfterFunc(d time.Duration, f func()) Timer
+	// NewTimer returns a Timer that fires once d has passed, like
+	// time.NewTimer.
+	NewTimer(d time.Duration) Timer
+	// NewTicker returns a Ticker that fires every d, like time.NewTicker.
+	// It panics if d is not positive.
+	NewTicker(d time.Duration) Ticker
+	// Sleep blocks until d has passed, like time.Sleep.
+	Sleep(d time.Duration)
+}
+
+// Timer is a single event, like *time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, reporting whether it was
+	// still pending.
+	Stop() bool
+	// Reset changes the timer to fire once d has passed, reporting whether
+	// it was still pending.
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers the time at intervals, like *time.Ticker.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the ticker.
+	Stop()
+	// Reset stops the ticker and restarts it with period d.
+	Reset(d time.Duration)
+}
+
+// Real returns the Clock backed by package time.
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal returns c, or Real if c is nil, so that components can treat a nil
+// Clock as the real one.
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time        { return r.t.C }
+func (r realTimer) Stop() bool                 { return r.t.Stop() }
+func (r realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time   { return r.t.C }
+func (r realTicker) Stop()                 { r.t.Stop() }
+func (r realTicker) Reset(d time.Duration) { r.t.Reset(d) }
@@ -0,0 +1,260 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock whose time only moves when Advance is called, making
+// time-dependent code deterministic in tests. Timers, tickers, After channels
+// and sleepers all fire from Advance, in order of their due time, with ties
+// fired in order of creation. All methods are safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond // broadcast whenever the set of pending timers changes
+	now     time.Time
+	seq     uint64
+	pending []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	ch     chan time.Time // nil for AfterFunc timers
+	fn     func()         // nil for channel timers
+	when   time.Time
+	seq    uint64
+	period time.Duration // positive for tickers
+	active bool
+}
+
+var (
+	_ Clock  = (*FakeClock)(nil)
+	_ Timer  = (*fakeTimer)(nil)
+	_ Ticker = (*fakeTicker)(nil)
+)
+
+// NewFake returns a FakeClock set to start.
+func NewFake(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the fake current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the fake time elapsed since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After returns a channel that receives the fake time once Advance has moved
+// the clock d forward.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// AfterFunc calls f once Advance has moved the clock d forward. Unlike
+// time.AfterFunc, f runs on the goroutine calling Advance, which waits for it
+// to return.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	t := &fakeTimer{clock: c, fn: f}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scheduleLocked(t, d)
+	return t
+}
+
+// NewTimer returns a Timer that fires once Advance has moved the clock d
+// forward. A timer with d <= 0 fires at once.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+	c.scheduleLocked(t, d)
+	return t
+}
+
+// NewTicker returns a Ticker that fires every time Advance moves the clock
+// past another multiple of d. Like time.Ticker, it drops ticks the receiver
+// is not ready for. It panics if d is not positive.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock.FakeClock.NewTicker: d must be positive")
+	}
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1), period: d}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scheduleLocked(t, d)
+	return &fakeTicker{t}
+}
+
+// Sleep blocks until Advance has moved the clock d forward.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d, firing every timer that falls due on
+// the way, one at a time in order of due time. When a timer fires, the clock
+// reads its due time.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for {
+		t := c.nextDueLocked(target)
+		if t == nil {
+			break
+		}
+		c.now = t.when
+		if t.period > 0 {
+			c.scheduleLocked(t, t.period)
+		} else {
+			c.removeLocked(t)
+		}
+		if t.fn != nil {
+			c.mu.Unlock()
+			t.fn()
+			c.mu.Lock()
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+	}
+	if target.After(c.now) {
+		c.now = target
+	}
+	c.mu.Unlock()
+}
+
+// BlockUntil blocks until at least n timers, tickers, After channels or
+// sleepers are pending, so a test can wait for the code under test to start
+// waiting before it calls Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.pending) < n {
+		c.changed.Wait()
+	}
+}
+
+// Waiters returns the number of pending timers, tickers, After channels and
+// sleepers.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// nextDueLocked returns the pending timer due first, if it is due by target.
+func (c *FakeClock) nextDueLocked(target time.Time) *fakeTimer {
+	var next *fakeTimer
+	for _, t := range c.pending {
+		if next == nil || t.when.Before(next.when) || (t.when.Equal(next.when) && t.seq < next.seq) {
+			next = t
+		}
+	}
+	if next == nil || next.when.After(target) {
+		return nil
+	}
+	return next
+}
+
+// scheduleLocked makes t due d from now, adding it to the pending timers if
+// it is not there already.
+func (c *FakeClock) scheduleLocked(t *fakeTimer, d time.Duration) {
+	c.seq++
+	t.when, t.seq = c.now.Add(d), c.seq
+	if !t.active {
+		t.active = true
+		c.pending = append(c.pending, t)
+		c.changed.Broadcast()
+	}
+}
+
+func (c *FakeClock) removeLocked(t *fakeTimer) bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, p := range c.pending {
+		if p == t {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			break
+		}
+	}
+	c.changed.Broadcast()
+	return true
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+// Stop stops the timer and, like time.Timer since Go 1.23, discards a fired
+// value not yet received.
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.drain()
+	return t.clock.removeLocked(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.drain()
+	wasActive := t.active
+	if d <= 0 && t.ch != nil {
+		t.clock.removeLocked(t)
+		t.ch <- t.clock.now
+		return wasActive
+	}
+	t.clock.scheduleLocked(t, d)
+	return wasActive
+}
+
+func (t *fakeTimer) drain() {
+	if t.ch == nil {
+		return
+	}
+	select {
+	case <-t.ch:
+	default:
+	}
+}
+
+type fakeTicker struct {
+	t *fakeTimer
+}
+
+func (k *fakeTicker) C() <-chan time.Time {
+	return k.t.ch
+}
+
+func (k *fakeTicker) Stop() {
+	k.t.Stop()
+}
+
+// Reset stops the ticker and restarts it with period d. It panics if d is
+// not positive.
+func (k *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock.FakeClock: ticker Reset needs a positive d")
+	}
+	c := k.t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k.t.drain()
+	k.t.period = d
+	c.scheduleLocked(k.t, d)
+}
@@ -0,0 +1,293 @@
+package clock_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fired(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClock_NowAndAdvance(t *testing.T) {
+	c := clock.NewFake(start)
+	if got := c.Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+	c.Advance(90 * time.Second)
+	if got := c.Now(); !got.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("Now() after Advance = %v, want %v", got, start.Add(90*time.Second))
+	}
+	if got := c.Since(start); got != 90*time.Second {
+		t.Errorf("Since(start) = %v, want 1m30s", got)
+	}
+}
+
+func TestFakeClock_Timer(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       time.Duration
+		advance time.Duration
+		want    bool
+	}{
+		{"not yet due", time.Second, 999 * time.Millisecond, false},
+		{"exactly due", time.Second, time.Second, true},
+		{"overdue", time.Second, time.Hour, true},
+		{"zero fires at once", 0, 0, true},
+		{"negative fires at once", -time.Second, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := clock.NewFake(start)
+			timer := c.NewTimer(tt.d)
+			c.Advance(tt.advance)
+			at, ok := fired(timer.C())
+			if ok != tt.want {
+				t.Fatalf("fired = %v, want %v", ok, tt.want)
+			}
+			if ok && tt.d > 0 && !at.Equal(start.Add(tt.d)) {
+				t.Errorf("fired at %v, want the due time %v", at, start.Add(tt.d))
+			}
+		})
+	}
+}
+
+func TestFakeClock_FiresInOrder(t *testing.T) {
+	c := clock.NewFake(start)
+	var got []string
+	record := func(name string) func() {
+		return func() { got = append(got, fmt.Sprintf("%s@%v", name, c.Since(start))) }
+	}
+	c.AfterFunc(3*time.Second, record("c"))
+	c.AfterFunc(time.Second, record("a"))
+	c.AfterFunc(2*time.Second, record("b1"))
+	c.AfterFunc(2*time.Second, record("b2"))
+
+	c.Advance(10 * time.Second)
+	want := []string{"a@1s", "b1@2s", "b2@2s", "c@3s"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fired %v, want %v", got, want)
+	}
+	if n := c.Waiters(); n != 0 {
+		t.Errorf("Waiters() = %d after all timers fired, want 0", n)
+	}
+}
+
+func TestFakeClock_AfterFuncSchedulesDuringAdvance(t *testing.T) {
+	c := clock.NewFake(start)
+	var got []time.Duration
+	var tick func()
+	tick = func() {
+		got = append(got, c.Since(start))
+		c.AfterFunc(time.Second, tick)
+	}
+	c.AfterFunc(time.Second, tick)
+
+	c.Advance(3500 * time.Millisecond)
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fired at %v, want %v", got, want)
+	}
+}
+
+func TestFakeClock_StopAndReset(t *testing.T) {
+	t.Run("Stop", func(t *testing.T) {
+		c := clock.NewFake(start)
+		timer := c.NewTimer(time.Second)
+		if !timer.Stop() {
+			t.Error("Stop() on a pending timer = false, want true")
+		}
+		if timer.Stop() {
+			t.Error("second Stop() = true, want false")
+		}
+		c.Advance(time.Hour)
+		if _, ok := fired(timer.C()); ok {
+			t.Error("stopped timer fired")
+		}
+	})
+
+	t.Run("Stop discards an unreceived fire", func(t *testing.T) {
+		c := clock.NewFake(start)
+		timer := c.NewTimer(time.Second)
+		c.Advance(time.Second)
+		if timer.Stop() {
+			t.Error("Stop() on a fired timer = true, want false")
+		}
+		if _, ok := fired(timer.C()); ok {
+			t.Error("value still buffered after Stop")
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		c := clock.NewFake(start)
+		timer := c.NewTimer(time.Second)
+		if !timer.Reset(5 * time.Second) {
+			t.Error("Reset() on a pending timer = false, want true")
+		}
+		c.Advance(4 * time.Second)
+		if _, ok := fired(timer.C()); ok {
+			t.Fatal("timer fired at its original due time")
+		}
+		c.Advance(time.Second)
+		if at, ok := fired(timer.C()); !ok || !at.Equal(start.Add(5*time.Second)) {
+			t.Errorf("fired = %v at %v, want true at %v", ok, at, start.Add(5*time.Second))
+		}
+		if timer.Reset(time.Second) {
+			t.Error("Reset() on a fired timer = true, want false")
+		}
+		c.Advance(time.Second)
+		if _, ok := fired(timer.C()); !ok {
+			t.Error("reset timer did not fire again")
+		}
+	})
+
+	t.Run("AfterFunc Stop", func(t *testing.T) {
+		c := clock.NewFake(start)
+		called := false
+		timer := c.AfterFunc(time.Second, func() { called = true })
+		timer.Stop()
+		c.Advance(time.Hour)
+		if called {
+			t.Error("stopped AfterFunc ran")
+		}
+	})
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	c := clock.NewFake(start)
+	ticker := c.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		c.Advance(time.Second)
+		at, ok := fired(ticker.C())
+		if !ok || !at.Equal(start.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("tick %d = %v at %v, want true at %v", i, ok, at, start.Add(time.Duration(i)*time.Second))
+		}
+	}
+
+	// Ticks the receiver is not ready for are dropped.
+	c.Advance(5 * time.Second)
+	if _, ok := fired(ticker.C()); !ok {
+		t.Fatal("no tick after Advance(5s)")
+	}
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("more than one tick buffered")
+	}
+
+	ticker.Reset(time.Minute)
+	c.Advance(59 * time.Second)
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("ticker fired before its new period")
+	}
+	c.Advance(time.Second)
+	if _, ok := fired(ticker.C()); !ok {
+		t.Error("ticker did not fire after its new period")
+	}
+
+	ticker.Stop()
+	c.Advance(time.Hour)
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("stopped ticker fired")
+	}
+}
+
+func TestFakeClock_NewTickerPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTicker(0) did not panic")
+		}
+	}()
+	clock.NewFake(start).NewTicker(0)
+}
+
+func TestFakeClock_SleepAndBlockUntil(t *testing.T) {
+	c := clock.NewFake(start)
+	var wg sync.WaitGroup
+	woke := make(chan time.Duration, 2)
+	for _, d := range []time.Duration{time.Second, 2 * time.Second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Sleep(d)
+			woke <- d
+		}()
+	}
+
+	c.BlockUntil(2)
+	c.Advance(time.Second)
+	if d := <-woke; d != time.Second {
+		t.Errorf("first sleeper woken = %v, want 1s", d)
+	}
+	c.Advance(time.Second)
+	wg.Wait()
+	if d := <-woke; d != 2*time.Second {
+		t.Errorf("second sleeper woken = %v, want 2s", d)
+	}
+}
+
+func TestReal(t *testing.T) {
+	c := clock.OrReal(nil)
+	before := time.Now()
+	if got := c.Now(); got.Before(before) {
+		t.Errorf("Now() = %v, before time.Now() %v", got, before)
+	}
+	select {
+	case <-c.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("After(1ms) did not fire within 1s")
+	}
+	timer := c.NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Error("Stop() on a pending real timer = false, want true")
+	}
+	done := make(chan struct{})
+	c.AfterFunc(time.Millisecond, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc(1ms) did not run within 1s")
+	}
+	if fake := clock.NewFake(start); clock.OrReal(fake) != clock.Clock(fake) {
+		t.Error("OrReal(c) did not return c")
+	}
+}
+
+func ExampleFakeClock() {
+	c := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Sleep(time.Hour)
+		fmt.Println("woke at", c.Now().Format(time.Kitchen))
+	}()
+
+	c.BlockUntil(1) // wait for the goroutine to start sleeping
+	c.Advance(time.Hour)
+	<-done
+	// Output: woke at 1:00AM
+}
+
+func BenchmarkFakeClock_Advance(b *testing.B) {
+	c := clock.NewFake(start)
+	for range 64 {
+		c.NewTicker(time.Second)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Advance(time.Second)
+	}
+}
@@ -0,0 +1,372 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// ErrBatcherClosed is returned when adding to a closed Batcher, and is the
+// error of items whose batch was abandoned because Close gave up waiting.
+var ErrBatcherClosed = errors.New("concurrency: batcher closed")
+
+// BatcherConfig sets when a Batcher flushes and how much it may hold. The
+// zero value flushes every 100 items, one batch at a time.
+type BatcherConfig[T any] struct {
+	// MaxItems flushes a batch once it holds this many items. Zero means 100.
+	MaxItems int
+	// MaxBytes, if positive, flushes a batch once its items' sizes add up to
+	// at least this many bytes. An item that would take a non-empty batch
+	// past the limit starts a new batch instead. Requires Size.
+	MaxBytes int
+	// Size reports an item's size in bytes for MaxBytes.
+	Size func(item T) int
+	// MaxWait, if positive, flushes a batch this long after its first item
+	// was added, however small it is.
+	MaxWait time.Duration
+	// MaxInFlight is the number of flushes that may run at once. Zero means
+	// 1, which also keeps batches in the order they were formed.
+	MaxInFlight int
+	// MaxPending bounds the items added but not yet flushed, including those
+	// being flushed. Add blocks while it is reached. It must be at least
+	// MaxItems, or a batch could never fill up. Zero means
+	// MaxItems * (MaxInFlight + 1).
+	MaxPending int
+	// Clock times MaxWait. Nil means clock.Real(). Tests can substitute a
+	// clock.FakeClock.
+	Clock clock.Clock
+}
+
+// ItemErrors lets a flush function report a separate outcome for each item
+// of a batch: returning an ItemErrors, possibly wrapped, with one entry per
+// item, gives every item its own error, nil for those that were persisted.
+// Any other error fails every item of the batch.
+type ItemErrors []error
+
+// Error returns the non-nil item errors, joined.
+func (e ItemErrors) Error() string {
+	if err := errors.Join(e...); err != nil {
+		return err.Error()
+	}
+	return "concurrency: no item errors"
+}
+
+// Unwrap returns the item errors.
+func (e ItemErrors) Unwrap() []error {
+	return e
+}
+
+// BatchReceipt is the handle for an item added to a Batcher. Its outcome
+// becomes available once Done is closed.
+type BatchReceipt struct {
+	done chan struct{}
+	err  error
+}
+
+// Done returns a channel that is closed once the item's batch was flushed.
+func (r *BatchReceipt) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait blocks until the item's batch was flushed and returns the item's
+// error, nil if it was persisted, or returns ctx.Err() if ctx is done first.
+func (r *BatchReceipt) Wait(ctx context.Context) error {
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type batch[T any] struct {
+	items    []T
+	receipts []*BatchReceipt
+	bytes    int
+	done     chan struct{} // closed once err is set
+	err      error
+}
+
+// Batcher accumulates items from concurrent writers into batches and hands
+// each batch to a flush function, for example to turn single-row inserts into
+// bulk inserts. A batch is flushed when it is full by item count or bytes,
+// when it is old enough, or on an explicit Flush or Close, whichever comes
+// first. Create one with NewBatcher and shut it down with Close. All methods
+// are safe for concurrent use.
+type Batcher[T any] struct {
+	cfg    BatcherConfig[T]
+	flush  func(ctx context.Context, items []T) error
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	slots  chan struct{} // one per pending item
+
+	mu         sync.Mutex
+	cond       *sync.Cond // signalled when ready grows or the batcher closes
+	current    *batch[T]  // the batch being filled; nil when empty
+	timer      clock.Timer
+	ready      []*batch[T] // formed batches waiting for a flush slot
+	unfinished map[*batch[T]]struct{}
+	closed     bool
+
+	closeOnce sync.Once
+	closing   chan struct{} // closed by Close, to release blocked Adds
+	done      chan struct{} // closed once every flush worker has exited
+}
+
+// NewBatcher returns a Batcher that hands batches to flush.
+//
+// Type Parameters:
+//
+//	T: The item type.
+//
+// Parameters:
+//
+//	flush: Persists a batch, which is never empty. Its context is cancelled
+//	       if Close gives up waiting. A panic in flush is recovered and fails
+//	       the batch with a *PanicError.
+//	cfg: When to flush and how much to hold.
+//
+// Returns:
+//
+//	*Batcher[T]: A running batcher.
+//
+// Panics if a limit in cfg is negative, if MaxPending is below MaxItems, or if
+// MaxBytes is set without Size.
+func NewBatcher[T any](flush func(ctx context.Context, items []T) error, cfg BatcherConfig[T]) *Batcher[T] {
+	if cfg.MaxItems < 0 || cfg.MaxBytes < 0 || cfg.MaxWait < 0 || cfg.MaxInFlight < 0 || cfg.MaxPending < 0 {
+		panic("concurrency.NewBatcher: limits must not be negative")
+	}
+	if cfg.MaxBytes > 0 && cfg.Size == nil {
+		panic("concurrency.NewBatcher: MaxBytes requires Size")
+	}
+	if cfg.MaxItems == 0 {
+		cfg.MaxItems = 100
+	}
+	if cfg.MaxInFlight == 0 {
+		cfg.MaxInFlight = 1
+	}
+	if cfg.MaxPending == 0 {
+		cfg.MaxPending = cfg.MaxItems * (cfg.MaxInFlight + 1)
+	}
+	if cfg.MaxPending < cfg.MaxItems {
+		panic("concurrency.NewBatcher: MaxPending must be at least MaxItems")
+	}
+	cfg.Clock = clock.OrReal(cfg.Clock)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	b := &Batcher[T]{
+		cfg:        cfg,
+		flush:      flush,
+		ctx:        ctx,
+		cancel:     cancel,
+		slots:      make(chan struct{}, cfg.MaxPending),
+		unfinished: make(map[*batch[T]]struct{}),
+		closing:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	var wg sync.WaitGroup
+	for range cfg.MaxInFlight {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.worker()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		cancel(nil)
+		close(b.done)
+	}()
+	return b
+}
+
+// Add adds item to the current batch, blocking while MaxPending items are
+// already pending. It fails with ctx.Err() if ctx is done while blocked, and
+// with ErrBatcherClosed once Close has been called.
+//
+// Returns:
+//
+//	*BatchReceipt: Reports whether the item was persisted.
+//	error: Why the item was not added.
+func (b *Batcher[T]) Add(ctx context.Context, item T) (*BatchReceipt, error) {
+	select {
+	case <-b.closing:
+		return nil, ErrBatcherClosed
+	default:
+	}
+	select {
+	case b.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.closing:
+		return nil, ErrBatcherClosed
+	}
+
+	size := 0
+	if b.cfg.MaxBytes > 0 {
+		size = b.cfg.Size(item)
+	}
+	r := &BatchReceipt{done: make(chan struct{})}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		<-b.slots
+		return nil, ErrBatcherClosed
+	}
+	if b.current != nil && b.cfg.MaxBytes > 0 && b.current.bytes+size > b.cfg.MaxBytes {
+		b.cutLocked()
+	}
+	if b.current == nil {
+		b.startBatchLocked()
+	}
+	c := b.current
+	c.items = append(c.items, item)
+	c.receipts = append(c.receipts, r)
+	c.bytes += size
+	if len(c.items) >= b.cfg.MaxItems || (b.cfg.MaxBytes > 0 && c.bytes >= b.cfg.MaxBytes) {
+		b.cutLocked()
+	}
+	return r, nil
+}
+
+// AddWait adds item and waits for its batch to be flushed, returning the
+// item's error.
+func (b *Batcher[T]) AddWait(ctx context.Context, item T) error {
+	r, err := b.Add(ctx, item)
+	if err != nil {
+		return err
+	}
+	return r.Wait(ctx)
+}
+
+// startBatchLocked opens a new current batch and, with MaxWait, arms its
+// timer.
+func (b *Batcher[T]) startBatchLocked() {
+	c := &batch[T]{done: make(chan struct{})}
+	b.current = c
+	b.unfinished[c] = struct{}{}
+	if b.cfg.MaxWait > 0 {
+		b.timer = b.cfg.Clock.AfterFunc(b.cfg.MaxWait, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.current == c {
+				b.cutLocked()
+			}
+		})
+	}
+}
+
+// cutLocked moves the current batch, if any, to the ready queue.
+func (b *Batcher[T]) cutLocked() {
+	if b.current == nil {
+		return
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.ready = append(b.ready, b.current)
+	b.current = nil
+	b.cond.Signal()
+}
+
+// Flush flushes the current batch without waiting for it to fill up, and
+// waits for it and every earlier batch to be flushed.
+//
+// Returns:
+//
+//	error: The errors of the batches waited for, joined, or ctx.Err() if
+//	       ctx is done first. Abandoning the wait does not stop the flushes.
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	b.cutLocked()
+	batches := make([]*batch[T], 0, len(b.unfinished))
+	for c := range b.unfinished {
+		batches = append(batches, c)
+	}
+	b.mu.Unlock()
+
+	errs := make([]error, 0, len(batches))
+	for _, c := range batches {
+		select {
+		case <-c.done:
+			errs = append(errs, c.err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops accepting items, flushes the current batch and waits for every
+// pending batch to be flushed. If ctx is done first, Close cancels the
+// running flushes, fails the batches not yet started with ErrBatcherClosed,
+// and returns ctx.Err(). Calling Close again waits again.
+func (b *Batcher[T]) Close(ctx context.Context) error {
+	b.closeOnce.Do(func() {
+		close(b.closing)
+		b.mu.Lock()
+		b.closed = true
+		b.cutLocked()
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	})
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		b.cancel(ErrBatcherClosed)
+		return ctx.Err()
+	}
+}
+
+func (b *Batcher[T]) worker() {
+	for {
+		b.mu.Lock()
+		for len(b.ready) == 0 && !b.closed {
+			b.cond.Wait()
+		}
+		if len(b.ready) == 0 {
+			b.mu.Unlock()
+			return
+		}
+		c := b.ready[0]
+		b.ready[0] = nil
+		b.ready = b.ready[1:]
+		b.mu.Unlock()
+		b.run(c)
+	}
+}
+
+// run flushes c and reports the outcome to its items.
+func (b *Batcher[T]) run(c *batch[T]) {
+	err := context.Cause(b.ctx)
+	if err == nil {
+		_, err = callSafely(func() (struct{}, error) { return struct{}{}, b.flush(b.ctx, c.items) })
+	}
+
+	var itemErrs ItemErrors
+	perItem := errors.As(err, &itemErrs) && len(itemErrs) == len(c.items)
+	for i, r := range c.receipts {
+		r.err = err
+		if perItem {
+			r.err = itemErrs[i]
+		}
+		close(r.done)
+	}
+
+	b.mu.Lock()
+	delete(b.unfinished, c)
+	b.mu.Unlock()
+	c.err = err
+	close(c.done)
+	for range c.items {
+		<-b.slots
+	}
+}
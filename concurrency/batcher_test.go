@@ -0,0 +1,345 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+// batchRecorder records the batches handed to its flush method.
+type batchRecorder[T any] struct {
+	mu      sync.Mutex
+	batches [][]T
+}
+
+func (r *batchRecorder[T]) flush(_ context.Context, items []T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]T(nil), items...))
+	return nil
+}
+
+func (r *batchRecorder[T]) get() [][]T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]T(nil), r.batches...)
+}
+
+func TestBatcher_FlushBySize(t *testing.T) {
+	checkGoroutineLeaks(t)
+	rec := &batchRecorder[int]{}
+	b := concurrency.NewBatcher(rec.flush, concurrency.BatcherConfig[int]{MaxItems: 3})
+
+	ctx := context.Background()
+	var receipts []*concurrency.BatchReceipt
+	for i := range 7 {
+		r, err := b.Add(ctx, i)
+		if err != nil {
+			t.Fatalf("Add(%d) error = %v", i, err)
+		}
+		receipts = append(receipts, r)
+	}
+	for i, r := range receipts[:6] {
+		if err := r.Wait(ctx); err != nil {
+			t.Errorf("item %d error = %v", i, err)
+		}
+	}
+	select {
+	case <-receipts[6].Done():
+		t.Error("item of an unfilled batch was flushed before Close")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := b.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	want := [][]int{{0, 1, 2}, {3, 4, 5}, {6}}
+	if got := rec.get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+	if _, err := b.Add(ctx, 7); !errors.Is(err, concurrency.ErrBatcherClosed) {
+		t.Errorf("Add() after Close error = %v, want ErrBatcherClosed", err)
+	}
+}
+
+func TestBatcher_FlushByBytes(t *testing.T) {
+	checkGoroutineLeaks(t)
+	rec := &batchRecorder[string]{}
+	b := concurrency.NewBatcher(rec.flush, concurrency.BatcherConfig[string]{
+		MaxBytes: 10,
+		Size:     func(s string) int { return len(s) },
+	})
+	for _, s := range []string{"aaaa", "bbbb", "cccc", "dddddddddddd", "ee", "ffffffff"} {
+		if _, err := b.Add(context.Background(), s); err != nil {
+			t.Fatalf("Add(%q) error = %v", s, err)
+		}
+	}
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// A batch is cut before an item would overflow it, and once it reaches
+	// the limit; an oversized item travels alone.
+	want := [][]string{{"aaaa", "bbbb"}, {"cccc"}, {"dddddddddddd"}, {"ee", "ffffffff"}}
+	if got := rec.get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want %v", got, want)
+	}
+}
+
+func TestBatcher_FlushByTime(t *testing.T) {
+	checkGoroutineLeaks(t)
+	clock := newFakeClock()
+	rec := &batchRecorder[int]{}
+	b := concurrency.NewBatcher(rec.flush, concurrency.BatcherConfig[int]{MaxItems: 100, MaxWait: time.Second, Clock: clock})
+	defer b.Close(context.Background())
+
+	r, err := b.Add(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	// The fake clock fires timers within Advance, so nothing can be late.
+	clock.Advance(999 * time.Millisecond)
+	select {
+	case <-r.Done():
+		t.Fatal("batch flushed before MaxWait")
+	default:
+	}
+	clock.Advance(time.Millisecond)
+	if err := r.Wait(context.Background()); err != nil {
+		t.Fatalf("item error = %v", err)
+	}
+	if got := rec.get(); !reflect.DeepEqual(got, [][]int{{1}}) {
+		t.Errorf("batches = %v, want [[1]]", got)
+	}
+	if n := clock.Waiters(); n != 0 {
+		t.Errorf("%d timers pending after the flush, want 0", n)
+	}
+}
+
+func TestBatcher_ExplicitFlush(t *testing.T) {
+	checkGoroutineLeaks(t)
+	rec := &batchRecorder[int]{}
+	b := concurrency.NewBatcher(rec.flush, concurrency.BatcherConfig[int]{MaxItems: 10})
+	defer b.Close(context.Background())
+
+	ctx := context.Background()
+	for i := range 4 {
+		b.Add(ctx, i)
+	}
+	if err := b.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := rec.get(); !reflect.DeepEqual(got, [][]int{{0, 1, 2, 3}}) {
+		t.Errorf("batches after Flush = %v", got)
+	}
+	if err := b.Flush(ctx); err != nil {
+		t.Errorf("Flush() with nothing pending error = %v", err)
+	}
+}
+
+func TestBatcher_ErrorPropagation(t *testing.T) {
+	checkGoroutineLeaks(t)
+	errDB := errors.New("db down")
+	b := concurrency.NewBatcher(func(_ context.Context, items []int) error {
+		switch items[0] {
+		case 0:
+			return fmt.Errorf("insert: %w", errDB)
+		case 2:
+			// Reject odd items individually.
+			errs := make(concurrency.ItemErrors, len(items))
+			for i, v := range items {
+				if v%2 == 1 {
+					errs[i] = fmt.Errorf("item %d rejected", v)
+				}
+			}
+			return errs
+		default:
+			panic("boom")
+		}
+	}, concurrency.BatcherConfig[int]{MaxItems: 2})
+
+	ctx := context.Background()
+	receipts := make([]*concurrency.BatchReceipt, 6)
+	for i := range receipts {
+		receipts[i], _ = b.Add(ctx, i)
+	}
+	if err := b.Flush(ctx); err == nil {
+		t.Error("Flush() error = nil, want the batch errors")
+	}
+	defer b.Close(ctx)
+
+	for i := range 2 {
+		if err := receipts[i].Wait(ctx); !errors.Is(err, errDB) {
+			t.Errorf("item %d error = %v, want errDB", i, err)
+		}
+	}
+	if err := receipts[2].Wait(ctx); err != nil {
+		t.Errorf("item 2 error = %v, want nil", err)
+	}
+	if err := receipts[3].Wait(ctx); err == nil || !strings.Contains(err.Error(), "item 3") {
+		t.Errorf("item 3 error = %v, want its own rejection", err)
+	}
+	var pe *concurrency.PanicError
+	if err := receipts[4].Wait(ctx); !errors.As(err, &pe) {
+		t.Errorf("item 4 error = %v, want *PanicError", err)
+	}
+}
+
+func TestBatcher_Backpressure(t *testing.T) {
+	checkGoroutineLeaks(t)
+	release := make(chan struct{})
+	b := concurrency.NewBatcher(func(context.Context, []int) error {
+		<-release
+		return nil
+	}, concurrency.BatcherConfig[int]{MaxItems: 2, MaxPending: 4})
+
+	ctx := context.Background()
+	for i := range 4 {
+		if _, err := b.Add(ctx, i); err != nil {
+			t.Fatalf("Add(%d) error = %v", i, err)
+		}
+	}
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := b.Add(short, 4); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Add() when full error = %v, want DeadlineExceeded", err)
+	}
+
+	close(release)
+	r, err := b.Add(ctx, 4)
+	if err != nil {
+		t.Fatalf("Add() after release error = %v", err)
+	}
+	if err := b.Close(ctx); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := r.Wait(ctx); err != nil {
+		t.Errorf("item added after release error = %v", err)
+	}
+}
+
+func TestBatcher_BoundedInFlight(t *testing.T) {
+	checkGoroutineLeaks(t)
+	var mu sync.Mutex
+	running, peak := 0, 0
+	release := make(chan struct{})
+	b := concurrency.NewBatcher(func(context.Context, []int) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}, concurrency.BatcherConfig[int]{MaxItems: 1, MaxInFlight: 3})
+
+	var wg sync.WaitGroup
+	for i := range 30 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.AddWait(context.Background(), i); err != nil {
+				t.Errorf("AddWait(%d) error = %v", i, err)
+			}
+		}()
+	}
+	eventually(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return running == 3
+	}, "3 flushes running")
+	close(release)
+	wg.Wait()
+	b.Close(context.Background())
+	if peak > 3 {
+		t.Errorf("peak concurrent flushes = %d, want at most 3", peak)
+	}
+}
+
+func TestBatcher_CloseTimeout(t *testing.T) {
+	checkGoroutineLeaks(t)
+	b := concurrency.NewBatcher(func(ctx context.Context, _ []int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, concurrency.BatcherConfig[int]{MaxItems: 1})
+
+	ctx := context.Background()
+	first, _ := b.Add(ctx, 1)
+	second, _ := b.Add(ctx, 2)
+
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := b.Close(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close() error = %v, want DeadlineExceeded", err)
+	}
+	if err := first.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("running flush error = %v, want Canceled", err)
+	}
+	if err := second.Wait(ctx); !errors.Is(err, concurrency.ErrBatcherClosed) {
+		t.Errorf("abandoned batch error = %v, want ErrBatcherClosed", err)
+	}
+	if err := b.Close(ctx); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestNewBatcher_Panics(t *testing.T) {
+	noop := func(context.Context, []string) error { return nil }
+	tests := []struct {
+		name string
+		cfg  concurrency.BatcherConfig[string]
+	}{
+		{"negative MaxItems", concurrency.BatcherConfig[string]{MaxItems: -1}},
+		{"negative MaxInFlight", concurrency.BatcherConfig[string]{MaxInFlight: -1}},
+		{"MaxBytes without Size", concurrency.BatcherConfig[string]{MaxBytes: 10}},
+		// A batch of 10 could never fill up with only 5 items pending.
+		{"MaxPending below MaxItems", concurrency.BatcherConfig[string]{MaxItems: 10, MaxPending: 5}},
+		{"MaxPending below default MaxItems", concurrency.BatcherConfig[string]{MaxPending: 99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("NewBatcher() did not panic")
+				}
+			}()
+			concurrency.NewBatcher(noop, tt.cfg)
+		})
+	}
+}
+
+func ExampleBatcher() {
+	b := concurrency.NewBatcher(func(_ context.Context, rows []string) error {
+		fmt.Println("INSERT", strings.Join(rows, ", "))
+		return nil
+	}, concurrency.BatcherConfig[string]{MaxItems: 2})
+
+	ctx := context.Background()
+	for _, row := range []string{"alice", "bob", "carol"} {
+		b.Add(ctx, row)
+	}
+	b.Close(ctx)
+	// Output:
+	// INSERT alice, bob
+	// INSERT carol
+}
+
+func BenchmarkBatcher_Add(b *testing.B) {
+	batcher := concurrency.NewBatcher(func(context.Context, []int) error { return nil },
+		concurrency.BatcherConfig[int]{MaxItems: 256, MaxInFlight: 2})
+	ctx := context.Background()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			batcher.Add(ctx, 1)
+		}
+	})
+	batcher.Close(ctx)
+}
@@ -0,0 +1,96 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrBulkheadFull is returned by a Bulkhead that rejects a call because all
+// of its slots are busy and its waiting line is full.
+var ErrBulkheadFull = errors.New("concurrency: bulkhead full")
+
+// BulkheadOption configures a Bulkhead.
+type BulkheadOption func(*bulkheadConfig)
+
+type bulkheadConfig struct {
+	maxWaiting int
+}
+
+// WithMaxWaiting lets up to n calls wait for a slot when all are busy; calls
+// beyond that fail at once with ErrBulkheadFull. It panics if n is negative.
+// The default is 0: a call that finds no free slot fails immediately.
+func WithMaxWaiting(n int) BulkheadOption {
+	if n < 0 {
+		panic("concurrency.WithMaxWaiting: n must not be negative")
+	}
+	return func(c *bulkheadConfig) { c.maxWaiting = n }
+}
+
+// Bulkhead limits how many calls to a dependency run at once, so that a slow
+// dependency ties up a bounded number of goroutines instead of all of them.
+// Use it through Guarded, or call Acquire directly. All methods are safe for
+// concurrent use.
+type Bulkhead struct {
+	cfg     bulkheadConfig
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+// NewBulkhead returns a Bulkhead that admits up to maxConcurrent calls at a
+// time. It panics if maxConcurrent is not positive.
+func NewBulkhead(maxConcurrent int, opts ...BulkheadOption) *Bulkhead {
+	if maxConcurrent <= 0 {
+		panic("concurrency.NewBulkhead: maxConcurrent must be positive")
+	}
+	b := &Bulkhead{slots: make(chan struct{}, maxConcurrent)}
+	for _, opt := range opts {
+		opt(&b.cfg)
+	}
+	return b
+}
+
+// Acquire implements Guard. It takes a free slot, or waits for one if the
+// waiting line has room, and fails with ErrBulkheadFull otherwise, or with
+// ctx.Err() if ctx is done while waiting. The release function frees the
+// slot; the call's error is ignored.
+func (b *Bulkhead) Acquire(ctx context.Context) (func(err error), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return b.release(), nil
+	default:
+	}
+
+	if b.waiting.Add(1) > int64(b.cfg.maxWaiting) {
+		b.waiting.Add(-1)
+		return nil, ErrBulkheadFull
+	}
+	defer b.waiting.Add(-1)
+	select {
+	case b.slots <- struct{}{}:
+		return b.release(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *Bulkhead) release() func(error) {
+	var once sync.Once
+	return func(error) {
+		once.Do(func() { <-b.slots })
+	}
+}
+
+// InFlight returns the number of calls currently holding a slot.
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// Waiting returns the number of calls currently waiting for a slot.
+func (b *Bulkhead) Waiting() int {
+	return int(b.waiting.Load())
+}
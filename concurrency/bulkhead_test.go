@@ -0,0 +1,103 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+func TestBulkhead_LimitsConcurrency(t *testing.T) {
+	checkGoroutineLeaks(t)
+	b := concurrency.NewBulkhead(3, concurrency.WithMaxWaiting(100))
+	var running, peak atomic.Int64
+	release := make(chan struct{})
+	work := concurrency.Guarded(func(context.Context) (int, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return 0, nil
+	}, b)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := work(context.Background()); err != nil {
+				t.Errorf("work() error = %v", err)
+			}
+		}()
+	}
+	eventually(t, time.Second, func() bool {
+		return running.Load() == 3 && b.Waiting() == 17
+	}, "3 calls running and 17 waiting")
+	close(release)
+	wg.Wait()
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+	if b.InFlight() != 0 || b.Waiting() != 0 {
+		t.Errorf("InFlight() = %d, Waiting() = %d after all calls", b.InFlight(), b.Waiting())
+	}
+}
+
+func TestBulkhead_Rejection(t *testing.T) {
+	checkGoroutineLeaks(t)
+	b := concurrency.NewBulkhead(1, concurrency.WithMaxWaiting(1))
+	ctx := context.Background()
+	release, err := b.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	waited := make(chan error, 1)
+	go func() {
+		r, err := b.Acquire(ctx)
+		if err == nil {
+			r(nil)
+		}
+		waited <- err
+	}()
+	eventually(t, time.Second, func() bool { return b.Waiting() == 1 }, "second caller never started waiting")
+
+	if _, err := b.Acquire(ctx); !errors.Is(err, concurrency.ErrBulkheadFull) {
+		t.Errorf("Acquire() with a full line error = %v, want ErrBulkheadFull", err)
+	}
+	short, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := b.Acquire(short); !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire(cancelled) error = %v, want Canceled", err)
+	}
+
+	release(nil)
+	release(nil) // releasing twice frees the slot only once
+	if err := <-waited; err != nil {
+		t.Errorf("waiting caller error = %v", err)
+	}
+	if b.InFlight() != 0 {
+		t.Errorf("InFlight() = %d, want 0", b.InFlight())
+	}
+}
+
+func BenchmarkGuarded(b *testing.B) {
+	fn := concurrency.Guarded(func(context.Context) (int, error) { return 1, nil },
+		concurrency.NewCircuitBreaker(), concurrency.NewBulkhead(64, concurrency.WithMaxWaiting(1<<20)))
+	ctx := context.Background()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			fn(ctx)
+		}
+	})
+}
@@ -0,0 +1,444 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// ErrBusClosed is returned when publishing to or subscribing to a closed Bus,
+// and by a Publish blocked on a full buffer when the bus is closed.
+var ErrBusClosed = errors.New("concurrency: bus closed")
+
+// OverflowPolicy decides what Publish does when an asynchronous subscriber's
+// buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes Publish wait for room in the buffer. No event is
+	// lost, but a slow subscriber slows down every publisher. This is the
+	// default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered event to make room.
+	OverflowDropOldest
+	// OverflowDropNewest discards the event being published.
+	OverflowDropNewest
+)
+
+// String returns a short name for the policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "Block"
+	case OverflowDropOldest:
+		return "DropOldest"
+	case OverflowDropNewest:
+		return "DropNewest"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+// SubscriberConfig sets which events a subscriber receives and how they are
+// delivered. The zero value receives every event asynchronously, through a
+// buffer of 64 events that blocks publishers when full.
+type SubscriberConfig[T any] struct {
+	// Filter, if set, selects the events the subscriber receives, like the
+	// predicate passed to Filter. It runs on the publishing goroutine before
+	// the event is buffered, so it must be quick.
+	Filter func(event T) bool
+	// Sync delivers events by calling the handler on the publishing
+	// goroutine, before Publish returns, instead of from the subscriber's own
+	// goroutine. BufferSize and Overflow are then ignored.
+	Sync bool
+	// BufferSize is the number of events buffered for an asynchronous
+	// subscriber. Zero means 64.
+	BufferSize int
+	// Overflow decides what Publish does when the buffer is full.
+	Overflow OverflowPolicy
+}
+
+// SubscriptionStats is a point-in-time snapshot of a subscription's counters.
+type SubscriptionStats struct {
+	Delivered uint64 // events handled
+	Dropped   uint64 // events discarded by the overflow policy
+	Pending   int    // events buffered, not yet handled
+	Slow      uint64 // deliveries reported to the slow-subscriber hook
+}
+
+// BusOption configures a Bus.
+type BusOption func(*busConfig)
+
+type busConfig struct {
+	slowThreshold time.Duration
+	onSlow        func(sub *Subscription, lag time.Duration)
+	clock         clock.Clock
+}
+
+// WithSlowSubscriberHook calls fn whenever a subscriber finishes handling an
+// event at least threshold after it was published, counting both the time
+// the event spent buffered and the handler's own run time. fn runs on the
+// goroutine that called the handler, so it must be quick. It panics if
+// threshold is not positive.
+func WithSlowSubscriberHook(threshold time.Duration, fn func(sub *Subscription, lag time.Duration)) BusOption {
+	if threshold <= 0 {
+		panic("concurrency.WithSlowSubscriberHook: threshold must be positive")
+	}
+	return func(c *busConfig) { c.slowThreshold, c.onSlow = threshold, fn }
+}
+
+// WithBusClock replaces the clock a bus measures delivery lag with, so tests
+// can control slow-subscriber detection with a clock.FakeClock. The default
+// is clock.Real().
+func WithBusClock(c clock.Clock) BusOption {
+	return func(cfg *busConfig) { cfg.clock = c }
+}
+
+// Bus is a typed publish/subscribe event bus. Publish hands an event to every
+// subscriber whose filter accepts it, either synchronously or through the
+// subscriber's own bounded buffer and goroutine, so that components can
+// exchange events without wiring channels between each other.
+//
+// Events published by one goroutine reach each subscriber in the order they
+// were published, less any dropped by its overflow policy. Create a Bus with
+// NewBus and shut it down with Close. All methods are safe for concurrent use.
+type Bus[T any] struct {
+	cfg busConfig
+
+	mu         sync.RWMutex
+	subs       []*subscriber[T] // copy-on-write; never modified in place
+	closed     bool
+	publishing sync.WaitGroup
+
+	closeOnce  sync.Once
+	closedSubs []*subscriber[T]
+	closing    chan struct{} // closed by Close, to release blocked publishers
+	done       chan struct{} // closed once every subscriber has stopped
+}
+
+type busEvent[T any] struct {
+	event T
+	at    time.Time // publication time; zero without a slow-subscriber hook
+}
+
+type subscriber[T any] struct {
+	sub     *Subscription
+	handler func(event T)
+	cfg     SubscriberConfig[T]
+	ch      chan busEvent[T] // nil for synchronous subscribers
+	drain   chan struct{}    // closed by Close: handle what is buffered, then exit
+}
+
+// Subscription is the handle returned by Subscribe.
+type Subscription struct {
+	async    bool
+	remove   func()
+	buffered func() int
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	slow      atomic.Uint64
+}
+
+// NewBus returns a Bus configured by opts.
+//
+// Type Parameters:
+//
+//	T: The event type.
+//
+// Parameters:
+//
+//	opts: Slow-subscriber detection and clock settings.
+//
+// Returns:
+//
+//	*Bus[T]: An open bus without subscribers.
+func NewBus[T any](opts ...BusOption) *Bus[T] {
+	var cfg busConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.clock = clock.OrReal(cfg.clock)
+	return &Bus[T]{
+		cfg:     cfg,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Subscribe registers handler for the events selected by cfg. An
+// asynchronous subscriber gets its own goroutine, which calls handler for
+// one event at a time; a synchronous one is called by Publish. A panic in
+// handler is not recovered.
+//
+// Returns:
+//
+//	*Subscription: The handle to unsubscribe with and read counters from.
+//	error: ErrBusClosed if the bus is closed.
+//
+// Panics if handler is nil or cfg.BufferSize is negative.
+func (b *Bus[T]) Subscribe(handler func(event T), cfg SubscriberConfig[T]) (*Subscription, error) {
+	if handler == nil {
+		panic("concurrency.Bus.Subscribe: handler must not be nil")
+	}
+	if cfg.BufferSize < 0 {
+		panic("concurrency.Bus.Subscribe: BufferSize must not be negative")
+	}
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = 64
+	}
+	s := &subscriber[T]{
+		handler: handler,
+		cfg:     cfg,
+		sub: &Subscription{
+			async: !cfg.Sync,
+			stop:  make(chan struct{}),
+			done:  make(chan struct{}),
+		},
+	}
+	if !cfg.Sync {
+		s.ch = make(chan busEvent[T], cfg.BufferSize)
+		s.drain = make(chan struct{})
+	}
+	s.sub.remove = func() { b.remove(s) }
+	s.sub.buffered = func() int { return len(s.ch) }
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, ErrBusClosed
+	}
+	b.subs = append(slices.Clip(b.subs), s)
+	b.mu.Unlock()
+	if !cfg.Sync {
+		go b.run(s)
+	}
+	return s.sub, nil
+}
+
+// Publish hands event to every subscriber whose filter accepts it, in the
+// order they subscribed. Synchronous subscribers have handled the event when
+// Publish returns; asynchronous ones have it buffered, or dropped it
+// according to their overflow policy.
+//
+// Returns:
+//
+//	error: ErrBusClosed if the bus is closed, or ctx.Err() if ctx is done
+//	       while waiting for room in an OverflowBlock buffer. Subscribers
+//	       after the one waited for then do not receive the event.
+func (b *Bus[T]) Publish(ctx context.Context, event T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	b.publishing.Add(1)
+	subs := b.subs
+	b.mu.RUnlock()
+	defer b.publishing.Done()
+
+	e := busEvent[T]{event: event}
+	if b.cfg.onSlow != nil {
+		e.at = b.cfg.clock.Now()
+	}
+	for _, s := range subs {
+		if s.cfg.Filter != nil && !s.cfg.Filter(event) {
+			continue
+		}
+		if err := b.offer(ctx, s, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// offer delivers e to s, or buffers it according to s's overflow policy.
+func (b *Bus[T]) offer(ctx context.Context, s *subscriber[T], e busEvent[T]) error {
+	select {
+	case <-s.sub.stop:
+		return nil
+	default:
+	}
+	if s.cfg.Sync {
+		b.deliver(s, e)
+		return nil
+	}
+
+	switch s.cfg.Overflow {
+	case OverflowDropNewest:
+		select {
+		case s.ch <- e:
+		default:
+			s.sub.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.ch <- e:
+				return nil
+			default:
+			}
+			select {
+			case <-s.ch:
+				s.sub.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- e:
+		case <-s.sub.stop:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.closing:
+			return ErrBusClosed
+		}
+	}
+	return nil
+}
+
+// run delivers the events buffered for s until it is unsubscribed, or until
+// the bus is closed and the buffer is empty.
+func (b *Bus[T]) run(s *subscriber[T]) {
+	defer close(s.sub.done)
+	draining := false
+	for {
+		var e busEvent[T]
+		if draining {
+			select {
+			case e = <-s.ch:
+			default:
+				return
+			}
+		} else {
+			select {
+			case <-s.sub.stop:
+				return
+			case e = <-s.ch:
+			case <-s.drain:
+				draining = true
+				continue
+			}
+		}
+		// Unsubscribe wins over events that were buffered at the same time.
+		select {
+		case <-s.sub.stop:
+			return
+		default:
+		}
+		b.deliver(s, e)
+	}
+}
+
+func (b *Bus[T]) deliver(s *subscriber[T], e busEvent[T]) {
+	s.handler(e.event)
+	s.sub.delivered.Add(1)
+	if b.cfg.onSlow == nil {
+		return
+	}
+	if lag := b.cfg.clock.Since(e.at); lag >= b.cfg.slowThreshold {
+		s.sub.slow.Add(1)
+		b.cfg.onSlow(s.sub, lag)
+	}
+}
+
+func (b *Bus[T]) remove(s *subscriber[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if i := slices.Index(b.subs, s); i >= 0 {
+		b.subs = slices.Delete(slices.Clone(b.subs), i, i+1)
+	}
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Bus[T]) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Close stops accepting events and subscriptions, fails publishers blocked on
+// a full buffer with ErrBusClosed, and waits for every subscriber to handle
+// the events already buffered for it. If ctx is done first, Close
+// unsubscribes everyone, discarding what is still buffered, and returns
+// ctx.Err(); handlers already running are not interrupted. Calling Close
+// again waits again.
+func (b *Bus[T]) Close(ctx context.Context) error {
+	b.closeOnce.Do(func() {
+		b.mu.Lock()
+		b.closed = true
+		b.closedSubs = b.subs
+		b.subs = nil
+		b.mu.Unlock()
+		close(b.closing)
+
+		go func() {
+			b.publishing.Wait()
+			for _, s := range b.closedSubs {
+				if s.drain != nil {
+					close(s.drain)
+				} else {
+					s.sub.Unsubscribe()
+				}
+			}
+			for _, s := range b.closedSubs {
+				<-s.sub.done
+			}
+			close(b.done)
+		}()
+	})
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		for _, s := range b.closedSubs {
+			s.sub.Unsubscribe()
+		}
+		return ctx.Err()
+	}
+}
+
+// Unsubscribe stops delivery to the subscription and discards the events
+// still buffered for it. A handler call already in progress is not
+// interrupted; wait on Done to know when it has returned. Unsubscribe may be
+// called more than once, and from within the handler.
+func (s *Subscription) Unsubscribe() {
+	s.stopOnce.Do(func() {
+		s.remove()
+		close(s.stop)
+		if !s.async {
+			close(s.done)
+		}
+	})
+}
+
+// Done returns a channel that is closed once the subscription has stopped:
+// for an asynchronous subscriber, once its goroutine has exited after
+// Unsubscribe or Close, so its handler is no longer running. For a
+// synchronous subscriber it is closed by Unsubscribe, while a concurrent
+// Publish may still be calling the handler.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Stats returns a snapshot of the subscription's counters.
+func (s *Subscription) Stats() SubscriptionStats {
+	return SubscriptionStats{
+		Delivered: s.delivered.Load(),
+		Dropped:   s.dropped.Load(),
+		Pending:   s.buffered(),
+		Slow:      s.slow.Load(),
+	}
+}
@@ -0,0 +1,453 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+// busRecorder collects the events handed to a subscriber.
+type busRecorder[T any] struct {
+	mu     sync.Mutex
+	events []T
+}
+
+func (r *busRecorder[T]) handle(event T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *busRecorder[T]) get() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]T(nil), r.events...)
+}
+
+// gatedHandler returns a handler that records events but blocks until gate is
+// closed, and a channel receiving each event as its handling starts.
+func gatedHandler(rec *busRecorder[int], gate <-chan struct{}) (func(int), <-chan int) {
+	started := make(chan int, 16)
+	return func(event int) {
+		started <- event
+		<-gate
+		rec.handle(event)
+	}, started
+}
+
+func TestBus_Filters(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	bus := concurrency.NewBus[int]()
+
+	tests := []struct {
+		name   string
+		filter func(int) bool
+		want   []int
+	}{
+		{"all", nil, []int{1, 2, 3, 4, 5, 6}},
+		{"even", func(n int) bool { return n%2 == 0 }, []int{2, 4, 6}},
+		{"none", func(int) bool { return false }, nil},
+	}
+	recs := make([]*busRecorder[int], len(tests))
+	for i, tt := range tests {
+		recs[i] = &busRecorder[int]{}
+		if _, err := bus.Subscribe(recs[i].handle, concurrency.SubscriberConfig[int]{Filter: tt.filter}); err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+	}
+	for n := 1; n <= 6; n++ {
+		if err := bus.Publish(ctx, n); err != nil {
+			t.Fatalf("Publish(%d) error = %v", n, err)
+		}
+	}
+	if err := bus.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recs[i].get(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBus_SyncDelivery(t *testing.T) {
+	ctx := context.Background()
+	bus := concurrency.NewBus[string]()
+	defer bus.Close(ctx)
+
+	rec := &busRecorder[string]{}
+	sub, err := bus.Subscribe(rec.handle, concurrency.SubscriberConfig[string]{Sync: true})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	for _, event := range []string{"a", "b"} {
+		bus.Publish(ctx, event)
+		if got := rec.get(); got[len(got)-1] != event {
+			t.Fatalf("after Publish(%q) handled %v", event, got)
+		}
+	}
+	if got := sub.Stats(); got.Delivered != 2 || got.Pending != 0 {
+		t.Errorf("Stats() = %+v, want 2 delivered, none pending", got)
+	}
+}
+
+func TestBus_Overflow(t *testing.T) {
+	tests := []struct {
+		policy      concurrency.OverflowPolicy
+		wantHandled []int
+	}{
+		{concurrency.OverflowDropNewest, []int{0, 1, 2}},
+		{concurrency.OverflowDropOldest, []int{0, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			checkGoroutineLeaks(t)
+			ctx := context.Background()
+			bus := concurrency.NewBus[int]()
+			rec := &busRecorder[int]{}
+			gate := make(chan struct{})
+			handler, started := gatedHandler(rec, gate)
+			sub, _ := bus.Subscribe(handler, concurrency.SubscriberConfig[int]{BufferSize: 2, Overflow: tt.policy})
+
+			bus.Publish(ctx, 0)
+			<-started // 0 is being handled; the buffer is empty
+			for n := 1; n <= 4; n++ {
+				if err := bus.Publish(ctx, n); err != nil {
+					t.Fatalf("Publish(%d) error = %v", n, err)
+				}
+			}
+			if got := sub.Stats(); got.Dropped != 2 || got.Pending != 2 {
+				t.Errorf("Stats() = %+v, want 2 dropped, 2 pending", got)
+			}
+			close(gate)
+			bus.Close(ctx)
+			if got := rec.get(); !reflect.DeepEqual(got, tt.wantHandled) {
+				t.Errorf("handled %v, want %v", got, tt.wantHandled)
+			}
+		})
+	}
+}
+
+func TestBus_OverflowBlock(t *testing.T) {
+	checkGoroutineLeaks(t)
+	bus := concurrency.NewBus[int]()
+	rec := &busRecorder[int]{}
+	gate := make(chan struct{})
+	handler, started := gatedHandler(rec, gate)
+	bus.Subscribe(handler, concurrency.SubscriberConfig[int]{BufferSize: 1})
+
+	bus.Publish(context.Background(), 0)
+	<-started
+	bus.Publish(context.Background(), 1) // fills the buffer
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bus.Publish(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Publish() on a full buffer = %v, want DeadlineExceeded", err)
+	}
+
+	published := make(chan error, 1)
+	go func() { published <- bus.Publish(context.Background(), 3) }()
+	select {
+	case err := <-published:
+		t.Fatalf("Publish() returned %v with the buffer full", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(gate)
+	if err := <-published; err != nil {
+		t.Errorf("Publish() after room was made = %v", err)
+	}
+	bus.Close(context.Background())
+	if got := rec.get(); !reflect.DeepEqual(got, []int{0, 1, 3}) {
+		t.Errorf("handled %v, want [0 1 3]", got)
+	}
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	bus := concurrency.NewBus[int]()
+	defer bus.Close(ctx)
+
+	tests := []struct {
+		name string
+		cfg  concurrency.SubscriberConfig[int]
+	}{
+		{"async", concurrency.SubscriberConfig[int]{}},
+		{"sync", concurrency.SubscriberConfig[int]{Sync: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &busRecorder[int]{}
+			sub, _ := bus.Subscribe(rec.handle, tt.cfg)
+			if n := bus.Subscribers(); n != 1 {
+				t.Fatalf("Subscribers() = %d, want 1", n)
+			}
+			bus.Publish(ctx, 1)
+			eventually(t, time.Second, func() bool { return len(rec.get()) == 1 }, "first event handled")
+
+			sub.Unsubscribe()
+			sub.Unsubscribe()
+			waitClosed(t, sub.Done(), "Done()")
+			if n := bus.Subscribers(); n != 0 {
+				t.Errorf("Subscribers() after Unsubscribe = %d, want 0", n)
+			}
+			bus.Publish(ctx, 2)
+			if got := rec.get(); !reflect.DeepEqual(got, []int{1}) {
+				t.Errorf("handled %v, want [1]", got)
+			}
+		})
+	}
+}
+
+func TestBus_UnsubscribeFromHandler(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	bus := concurrency.NewBus[int]()
+	defer bus.Close(ctx)
+
+	var sub *concurrency.Subscription
+	ready := make(chan struct{})
+	handled := 0
+	sub, _ = bus.Subscribe(func(int) {
+		<-ready
+		handled++
+		sub.Unsubscribe()
+	}, concurrency.SubscriberConfig[int]{})
+	close(ready)
+	for n := range 3 {
+		bus.Publish(ctx, n)
+	}
+	waitClosed(t, sub.Done(), "Done()")
+	if handled != 1 {
+		t.Errorf("handler ran %d times, want 1", handled)
+	}
+}
+
+func TestBus_SlowSubscriber(t *testing.T) {
+	// Each handler call takes as long as its event says, in fake time.
+	tests := []struct {
+		name      string
+		cfg       concurrency.SubscriberConfig[time.Duration]
+		events    []time.Duration
+		wantLags  []time.Duration
+		wantStats concurrency.SubscriptionStats
+	}{
+		{
+			name:      "slow handler",
+			cfg:       concurrency.SubscriberConfig[time.Duration]{Sync: true},
+			events:    []time.Duration{10 * time.Millisecond, 2 * time.Second, 999 * time.Millisecond},
+			wantLags:  []time.Duration{2 * time.Second},
+			wantStats: concurrency.SubscriptionStats{Delivered: 3, Slow: 1},
+		},
+		{
+			name:      "time spent buffered counts",
+			events:    []time.Duration{2 * time.Second, 0},
+			wantLags:  []time.Duration{2 * time.Second, 2 * time.Second},
+			wantStats: concurrency.SubscriptionStats{Delivered: 2, Slow: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGoroutineLeaks(t)
+			clock := newFakeClock()
+			var mu sync.Mutex
+			var lags []time.Duration
+			bus := concurrency.NewBus[time.Duration](
+				concurrency.WithBusClock(clock),
+				concurrency.WithSlowSubscriberHook(time.Second, func(_ *concurrency.Subscription, lag time.Duration) {
+					mu.Lock()
+					defer mu.Unlock()
+					lags = append(lags, lag)
+				}))
+
+			// Hold asynchronous delivery back until every event is published.
+			gate := make(chan struct{})
+			sub, _ := bus.Subscribe(func(d time.Duration) {
+				<-gate
+				clock.Advance(d)
+			}, tt.cfg)
+			if tt.cfg.Sync {
+				close(gate)
+			}
+			ctx := context.Background()
+			for _, d := range tt.events {
+				bus.Publish(ctx, d)
+			}
+			if !tt.cfg.Sync {
+				close(gate)
+			}
+			bus.Close(ctx)
+
+			if !reflect.DeepEqual(lags, tt.wantLags) {
+				t.Errorf("slow lags = %v, want %v", lags, tt.wantLags)
+			}
+			if got := sub.Stats(); got != tt.wantStats {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.wantStats)
+			}
+		})
+	}
+}
+
+func TestBus_Close(t *testing.T) {
+	t.Run("drains buffered events", func(t *testing.T) {
+		checkGoroutineLeaks(t)
+		ctx := context.Background()
+		bus := concurrency.NewBus[int]()
+		rec := &busRecorder[int]{}
+		gate := make(chan struct{})
+		handler, started := gatedHandler(rec, gate)
+		bus.Subscribe(handler, concurrency.SubscriberConfig[int]{})
+		for n := range 5 {
+			bus.Publish(ctx, n)
+		}
+		<-started
+		close(gate)
+		if err := bus.Close(ctx); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if got := rec.get(); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4}) {
+			t.Errorf("handled %v, want [0 1 2 3 4]", got)
+		}
+		if err := bus.Publish(ctx, 5); !errors.Is(err, concurrency.ErrBusClosed) {
+			t.Errorf("Publish() after Close = %v, want ErrBusClosed", err)
+		}
+		if _, err := bus.Subscribe(rec.handle, concurrency.SubscriberConfig[int]{}); !errors.Is(err, concurrency.ErrBusClosed) {
+			t.Errorf("Subscribe() after Close = %v, want ErrBusClosed", err)
+		}
+		if err := bus.Close(ctx); err != nil {
+			t.Errorf("second Close() error = %v", err)
+		}
+	})
+
+	t.Run("releases blocked publishers", func(t *testing.T) {
+		checkGoroutineLeaks(t)
+		bus := concurrency.NewBus[int]()
+		gate := make(chan struct{})
+		handler, started := gatedHandler(&busRecorder[int]{}, gate)
+		bus.Subscribe(handler, concurrency.SubscriberConfig[int]{BufferSize: 1})
+		bus.Publish(context.Background(), 0)
+		<-started
+		bus.Publish(context.Background(), 1)
+
+		published := make(chan error, 1)
+		go func() { published <- bus.Publish(context.Background(), 2) }()
+		closed := make(chan error, 1)
+		go func() { closed <- bus.Close(context.Background()) }()
+		if err := <-published; !errors.Is(err, concurrency.ErrBusClosed) {
+			t.Errorf("blocked Publish() = %v, want ErrBusClosed", err)
+		}
+		close(gate)
+		if err := <-closed; err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+
+	t.Run("gives up when ctx is done", func(t *testing.T) {
+		checkGoroutineLeaks(t)
+		bus := concurrency.NewBus[int]()
+		rec := &busRecorder[int]{}
+		gate := make(chan struct{})
+		handler, started := gatedHandler(rec, gate)
+		sub, _ := bus.Subscribe(handler, concurrency.SubscriberConfig[int]{})
+		for n := range 3 {
+			bus.Publish(context.Background(), n)
+		}
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := bus.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Close() = %v, want DeadlineExceeded", err)
+		}
+		close(gate)
+		waitClosed(t, sub.Done(), "Done()")
+		if got := rec.get(); !reflect.DeepEqual(got, []int{0}) {
+			t.Errorf("handled %v, want only the event in progress [0]", got)
+		}
+	})
+}
+
+func TestBus_ConcurrentPublishers(t *testing.T) {
+	checkGoroutineLeaks(t)
+	const publishers, perPublisher = 8, 200
+	ctx := context.Background()
+	bus := concurrency.NewBus[[2]int]()
+	rec := &busRecorder[[2]int]{}
+	bus.Subscribe(rec.handle, concurrency.SubscriberConfig[[2]int]{BufferSize: 4})
+
+	var wg sync.WaitGroup
+	for p := range publishers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perPublisher {
+				bus.Publish(ctx, [2]int{p, i})
+			}
+		}()
+	}
+	wg.Wait()
+	bus.Close(ctx)
+
+	got := rec.get()
+	if len(got) != publishers*perPublisher {
+		t.Fatalf("handled %d events, want %d", len(got), publishers*perPublisher)
+	}
+	next := make([]int, publishers)
+	for _, e := range got {
+		if e[1] != next[e[0]] {
+			t.Fatalf("publisher %d: event %d handled out of order, want %d", e[0], e[1], next[e[0]])
+		}
+		next[e[0]]++
+	}
+}
+
+func ExampleBus() {
+	ctx := context.Background()
+	bus := concurrency.NewBus[string]()
+
+	bus.Subscribe(func(event string) {
+		fmt.Println("audit:", event)
+	}, concurrency.SubscriberConfig[string]{Sync: true})
+	bus.Subscribe(func(event string) {
+		fmt.Println("alert:", event)
+	}, concurrency.SubscriberConfig[string]{
+		Sync:   true,
+		Filter: func(event string) bool { return event == "login failed" },
+	})
+
+	bus.Publish(ctx, "login ok")
+	bus.Publish(ctx, "login failed")
+	bus.Close(ctx)
+	// Output:
+	// audit: login ok
+	// audit: login failed
+	// alert: login failed
+}
+
+func BenchmarkBus_Publish(b *testing.B) {
+	for _, subs := range []int{1, 8} {
+		b.Run(fmt.Sprintf("subscribers=%d", subs), func(b *testing.B) {
+			ctx := context.Background()
+			bus := concurrency.NewBus[int]()
+			for range subs {
+				bus.Subscribe(func(int) {}, concurrency.SubscriberConfig[int]{BufferSize: 1024})
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				bus.Publish(ctx, i)
+			}
+			bus.Close(ctx)
+		})
+	}
+}
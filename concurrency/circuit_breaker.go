@@ -0,0 +1,352 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// ErrBreakerOpen is returned by a CircuitBreaker that rejects a call because
+// it is open, or half-open with all of its probes already admitted.
+var ErrBreakerOpen = errors.New("concurrency: circuit breaker open")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed admits every call and counts failures. This is the
+	// initial state.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every call until the open timeout has passed.
+	BreakerOpen
+	// BreakerHalfOpen admits a limited number of probe calls to find out
+	// whether the dependency has recovered.
+	BreakerHalfOpen
+)
+
+// String returns a short name for the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "Closed"
+	case BreakerOpen:
+		return "Open"
+	case BreakerHalfOpen:
+		return "HalfOpen"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// BreakerCounts are the calls a CircuitBreaker has seen in its rolling
+// window.
+type BreakerCounts struct {
+	Requests int
+	Failures int
+}
+
+// BreakerOption configures a CircuitBreaker.
+type BreakerOption func(*breakerConfig)
+
+type breakerConfig struct {
+	failureThreshold int
+	failureRatio     float64
+	minRequests      int
+	window           time.Duration
+	buckets          int
+	openTimeout      time.Duration
+	probes           int
+	isFailure        func(error) bool
+	onStateChange    func(from, to BreakerState)
+	clock            clock.Clock
+}
+
+// WithFailureThreshold opens the breaker once n calls have failed within the
+// rolling window. It panics if n is not positive. This is the default
+// policy, with n = 5, unless WithFailureRatio is given.
+func WithFailureThreshold(n int) BreakerOption {
+	if n <= 0 {
+		panic("concurrency.WithFailureThreshold: n must be positive")
+	}
+	return func(c *breakerConfig) { c.failureThreshold = n }
+}
+
+// WithFailureRatio opens the breaker once at least ratio of the calls within
+// the rolling window have failed, provided the window holds at least
+// minRequests calls. Combined with WithFailureThreshold, either condition
+// opens the breaker. It panics if ratio is not in (0, 1] or minRequests is
+// not positive.
+func WithFailureRatio(ratio float64, minRequests int) BreakerOption {
+	if !(ratio > 0 && ratio <= 1) || minRequests <= 0 {
+		panic("concurrency.WithFailureRatio: ratio must be in (0, 1] and minRequests positive")
+	}
+	return func(c *breakerConfig) { c.failureRatio, c.minRequests = ratio, minRequests }
+}
+
+// WithRollingWindow sets how far back the breaker counts calls, and into how
+// many buckets the window is divided; counts expire a bucket at a time. It
+// panics if d or buckets is not positive. The default is 10 seconds in 10
+// buckets.
+func WithRollingWindow(d time.Duration, buckets int) BreakerOption {
+	if d <= 0 || buckets <= 0 {
+		panic("concurrency.WithRollingWindow: d and buckets must be positive")
+	}
+	return func(c *breakerConfig) { c.window, c.buckets = d, buckets }
+}
+
+// WithOpenTimeout sets how long the breaker stays open before it turns
+// half-open. It panics if d is not positive. The default is 30 seconds.
+func WithOpenTimeout(d time.Duration) BreakerOption {
+	if d <= 0 {
+		panic("concurrency.WithOpenTimeout: d must be positive")
+	}
+	return func(c *breakerConfig) { c.openTimeout = d }
+}
+
+// WithHalfOpenProbes sets how many calls a half-open breaker admits. The
+// breaker closes once all n have succeeded and reopens at the first failure.
+// It panics if n is not positive. The default is 1.
+func WithHalfOpenProbes(n int) BreakerOption {
+	if n <= 0 {
+		panic("concurrency.WithHalfOpenProbes: n must be positive")
+	}
+	return func(c *breakerConfig) { c.probes = n }
+}
+
+// WithFailurePredicate decides which errors count as failures. By default
+// every non-nil error does, except context.Canceled, which usually means the
+// caller gave up rather than that the dependency failed. Errors that are not
+// failures are neutral: they are not counted as requests, and a half-open
+// probe that ends in one neither closes the breaker nor uses up its slot.
+func WithFailurePredicate(isFailure func(err error) bool) BreakerOption {
+	return func(c *breakerConfig) { c.isFailure = isFailure }
+}
+
+// WithStateChangeHook calls fn on every state change, for logging or
+// metrics. fn runs synchronously with the breaker locked, so it must be
+// quick and must not call the breaker's methods.
+func WithStateChangeHook(fn func(from, to BreakerState)) BreakerOption {
+	return func(c *breakerConfig) { c.onStateChange = fn }
+}
+
+// WithBreakerClock replaces the clock of a breaker, so tests can control the
+// rolling window and the open timeout with a clock.FakeClock. The default is
+// clock.Real().
+func WithBreakerClock(c clock.Clock) BreakerOption {
+	return func(cfg *breakerConfig) { cfg.clock = c }
+}
+
+// CircuitBreaker stops calls to a failing dependency so that it is not
+// hammered while it is down, and callers fail fast with ErrBreakerOpen
+// instead of waiting for timeouts.
+//
+// A closed breaker admits every call and counts outcomes over a rolling
+// window. When failures reach the configured threshold it opens and rejects
+// calls. After the open timeout it turns half-open and admits a few probe
+// calls: if they all succeed it closes, and if any fails it opens again.
+//
+// Use it through Guarded, or call Acquire directly. All methods are safe for
+// concurrent use.
+type CircuitBreaker struct {
+	cfg         breakerConfig
+	bucketWidth time.Duration
+	base        time.Time // bucket slots are counted from here
+
+	mu       sync.Mutex
+	state    BreakerState
+	gen      uint64 // incremented on every state change
+	buckets  []breakerBucket
+	openedAt time.Time
+	admitted int // probes admitted while half-open
+	passed   int // probes succeeded while half-open
+}
+
+type breakerBucket struct {
+	epoch    int64 // index of the bucket-wide time slot it counts, from base
+	requests int
+	failures int
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker configured by opts.
+func NewCircuitBreaker(opts ...BreakerOption) *CircuitBreaker {
+	cfg := breakerConfig{
+		window:      10 * time.Second,
+		buckets:     10,
+		openTimeout: 30 * time.Second,
+		probes:      1,
+		isFailure:   func(err error) bool { return err != nil && !errors.Is(err, context.Canceled) },
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.failureThreshold == 0 && cfg.failureRatio == 0 {
+		cfg.failureThreshold = 5
+	}
+	cfg.clock = clock.OrReal(cfg.clock)
+	return &CircuitBreaker{
+		cfg:         cfg,
+		bucketWidth: max(cfg.window/time.Duration(cfg.buckets), 1),
+		base:        cfg.clock.Now(),
+		buckets:     make([]breakerBucket, cfg.buckets),
+	}
+}
+
+// State returns the current state. An open breaker whose timeout has passed
+// reports BreakerHalfOpen.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refreshLocked(b.cfg.clock.Now())
+	return b.state
+}
+
+// Counts returns the calls counted in the current rolling window. The window
+// is cleared on every state change.
+func (b *CircuitBreaker) Counts() BreakerCounts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.countsLocked(b.cfg.clock.Now())
+}
+
+// Reset closes the breaker and clears its counts.
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setStateLocked(BreakerClosed, b.cfg.clock.Now())
+}
+
+// Acquire implements Guard. It fails with ErrBreakerOpen when the breaker is
+// open, or half-open with every probe already admitted, and otherwise
+// returns the release function to report the call's outcome with.
+func (b *CircuitBreaker) Acquire(ctx context.Context) (func(err error), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refreshLocked(b.cfg.clock.Now())
+	switch b.state {
+	case BreakerOpen:
+		return nil, ErrBreakerOpen
+	case BreakerHalfOpen:
+		if b.admitted >= b.cfg.probes {
+			return nil, ErrBreakerOpen
+		}
+		b.admitted++
+	}
+
+	gen := b.gen
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() { b.record(gen, err) })
+	}, nil
+}
+
+// refreshLocked turns an open breaker half-open once its timeout has passed.
+func (b *CircuitBreaker) refreshLocked(now time.Time) {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= b.cfg.openTimeout {
+		b.setStateLocked(BreakerHalfOpen, now)
+	}
+}
+
+// record reports the outcome of a call admitted during generation gen.
+// Outcomes of calls admitted before the last state change are ignored.
+func (b *CircuitBreaker) record(gen uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.gen {
+		return
+	}
+	now := b.cfg.clock.Now()
+	// A call that was never made, or that ended in an error the predicate
+	// ignores, says nothing about the dependency: it is not counted, and a
+	// half-open probe slot it held is given back.
+	failed := err != nil && b.cfg.isFailure(err)
+	if errors.Is(err, ErrCallNotMade) || (err != nil && !failed) {
+		if b.state == BreakerHalfOpen {
+			b.admitted--
+		}
+		return
+	}
+
+	switch b.state {
+	case BreakerClosed:
+		bucket := b.bucketLocked(now)
+		bucket.requests++
+		if failed {
+			bucket.failures++
+		}
+		// A success can trip a ratio threshold too, by reaching minRequests.
+		if b.shouldTripLocked(now) {
+			b.setStateLocked(BreakerOpen, now)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			b.setStateLocked(BreakerOpen, now)
+			return
+		}
+		b.passed++
+		if b.passed >= b.cfg.probes {
+			b.setStateLocked(BreakerClosed, now)
+		}
+	}
+}
+
+func (b *CircuitBreaker) shouldTripLocked(now time.Time) bool {
+	c := b.countsLocked(now)
+	if b.cfg.failureThreshold > 0 && c.Failures >= b.cfg.failureThreshold {
+		return true
+	}
+	return b.cfg.failureRatio > 0 && c.Requests >= b.cfg.minRequests &&
+		float64(c.Failures) >= b.cfg.failureRatio*float64(c.Requests)
+}
+
+// epoch returns the index of the bucket-wide time slot that now falls in.
+// Slots are counted from the breaker's creation rather than the Unix epoch,
+// so that clocks set before 1970, such as a FakeClock at the zero time, work.
+func (b *CircuitBreaker) epoch(now time.Time) int64 {
+	return int64(now.Sub(b.base) / b.bucketWidth)
+}
+
+// bucketLocked returns the bucket for now, clearing it if it last counted an
+// earlier slot.
+func (b *CircuitBreaker) bucketLocked(now time.Time) *breakerBucket {
+	epoch := b.epoch(now)
+	n := int64(len(b.buckets))
+	// Normalise the index, in case the clock was set back past base.
+	bucket := &b.buckets[(epoch%n+n)%n]
+	if bucket.epoch != epoch {
+		*bucket = breakerBucket{epoch: epoch}
+	}
+	return bucket
+}
+
+func (b *CircuitBreaker) countsLocked(now time.Time) BreakerCounts {
+	epoch := b.epoch(now)
+	var c BreakerCounts
+	for _, bucket := range b.buckets {
+		if age := epoch - bucket.epoch; age >= 0 && age < int64(len(b.buckets)) {
+			c.Requests += bucket.requests
+			c.Failures += bucket.failures
+		}
+	}
+	return c
+}
+
+func (b *CircuitBreaker) setStateLocked(to BreakerState, now time.Time) {
+	from := b.state
+	b.state = to
+	b.gen++
+	clear(b.buckets)
+	b.admitted, b.passed = 0, 0
+	if to == BreakerOpen {
+		b.openedAt = now
+	}
+	if from != to && b.cfg.onStateChange != nil {
+		b.cfg.onStateChange(from, to)
+	}
+}
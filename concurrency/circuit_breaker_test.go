@@ -0,0 +1,345 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+var errDown = errors.New("dependency down")
+
+// call runs one call through the breaker with the given outcome.
+func call(b *concurrency.CircuitBreaker, err error) error {
+	release, aerr := b.Acquire(context.Background())
+	if aerr != nil {
+		return aerr
+	}
+	release(err)
+	return err
+}
+
+func TestCircuitBreaker_CountThreshold(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrency.NewCircuitBreaker(
+		concurrency.WithFailureThreshold(3),
+		concurrency.WithOpenTimeout(time.Minute),
+		concurrency.WithBreakerClock(clock),
+	)
+	for range 2 {
+		call(b, errDown)
+	}
+	call(b, nil)
+	if b.State() != concurrency.BreakerClosed {
+		t.Fatalf("State() after 2 failures = %v, want Closed", b.State())
+	}
+	if c := b.Counts(); c != (concurrency.BreakerCounts{Requests: 3, Failures: 2}) {
+		t.Errorf("Counts() = %+v", c)
+	}
+	call(b, errDown)
+	if b.State() != concurrency.BreakerOpen {
+		t.Fatalf("State() after 3 failures = %v, want Open", b.State())
+	}
+	if err := call(b, nil); !errors.Is(err, concurrency.ErrBreakerOpen) {
+		t.Errorf("call on open breaker error = %v, want ErrBreakerOpen", err)
+	}
+
+	clock.Advance(time.Minute)
+	if b.State() != concurrency.BreakerHalfOpen {
+		t.Fatalf("State() after the open timeout = %v, want HalfOpen", b.State())
+	}
+	if err := call(b, nil); err != nil {
+		t.Errorf("probe error = %v", err)
+	}
+	if b.State() != concurrency.BreakerClosed {
+		t.Errorf("State() after a successful probe = %v, want Closed", b.State())
+	}
+}
+
+func TestCircuitBreaker_RollingWindow(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrency.NewCircuitBreaker(
+		concurrency.WithFailureThreshold(3),
+		concurrency.WithRollingWindow(10*time.Second, 10),
+		concurrency.WithBreakerClock(clock),
+	)
+	call(b, errDown)
+	call(b, errDown)
+	clock.Advance(11 * time.Second) // both failures expire
+	call(b, errDown)
+	if b.State() != concurrency.BreakerClosed {
+		t.Errorf("State() = %v, want Closed: old failures should have expired", b.State())
+	}
+	if c := b.Counts(); c.Failures != 1 {
+		t.Errorf("Counts().Failures = %d, want 1", c.Failures)
+	}
+}
+
+func TestCircuitBreaker_PreEpochClock(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+	}{
+		{"zero time", time.Time{}},
+		{"before 1970", time.Date(1969, 12, 31, 23, 59, 57, 300, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewFake(tt.start)
+			b := concurrency.NewCircuitBreaker(
+				concurrency.WithFailureThreshold(3),
+				concurrency.WithRollingWindow(10*time.Second, 10),
+				concurrency.WithBreakerClock(clk),
+			)
+			for range 2 {
+				call(b, errDown)
+				clk.Advance(1500 * time.Millisecond)
+			}
+			if c := b.Counts(); c != (concurrency.BreakerCounts{Requests: 2, Failures: 2}) {
+				t.Errorf("Counts() = %+v, want 2 failed requests", c)
+			}
+			clk.Advance(10 * time.Second)
+			if c := b.Counts(); c.Requests != 0 {
+				t.Errorf("Counts() after the window = %+v, want empty", c)
+			}
+			for range 3 {
+				call(b, errDown)
+			}
+			if b.State() != concurrency.BreakerOpen {
+				t.Errorf("State() after 3 failures = %v, want Open", b.State())
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_FailureRatio(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrency.NewCircuitBreaker(
+		concurrency.WithFailureRatio(0.5, 4),
+		concurrency.WithBreakerClock(clock),
+	)
+	call(b, errDown)
+	call(b, errDown)
+	call(b, errDown)
+	if b.State() != concurrency.BreakerClosed {
+		t.Fatalf("State() below minRequests = %v, want Closed", b.State())
+	}
+	call(b, nil)
+	if b.State() != concurrency.BreakerOpen {
+		t.Errorf("State() at 3/4 failures = %v, want Open", b.State())
+	}
+
+	b.Reset()
+	for range 6 {
+		call(b, nil)
+	}
+	call(b, errDown)
+	call(b, errDown)
+	if b.State() != concurrency.BreakerClosed {
+		t.Errorf("State() at 2/8 failures = %v, want Closed", b.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	clock := newFakeClock()
+	var mu sync.Mutex
+	var transitions []string
+	b := concurrency.NewCircuitBreaker(
+		concurrency.WithFailureThreshold(1),
+		concurrency.WithOpenTimeout(time.Second),
+		concurrency.WithHalfOpenProbes(2),
+		concurrency.WithBreakerClock(clock),
+		concurrency.WithStateChangeHook(func(from, to concurrency.BreakerState) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, from.String()+"->"+to.String())
+		}),
+	)
+	ctx := context.Background()
+	call(b, errDown)
+	clock.Advance(time.Second)
+
+	r1, err1 := b.Acquire(ctx)
+	r2, err2 := b.Acquire(ctx)
+	if err1 != nil || err2 != nil {
+		t.Fatalf("probe Acquire errors = %v, %v", err1, err2)
+	}
+	if _, err := b.Acquire(ctx); !errors.Is(err, concurrency.ErrBreakerOpen) {
+		t.Errorf("third probe error = %v, want ErrBreakerOpen", err)
+	}
+	r1(nil)
+	if b.State() != concurrency.BreakerHalfOpen {
+		t.Errorf("State() after one of two probes = %v, want HalfOpen", b.State())
+	}
+	r2(errDown)
+	if b.State() != concurrency.BreakerOpen {
+		t.Errorf("State() after a failed probe = %v, want Open", b.State())
+	}
+
+	clock.Advance(time.Second)
+	call(b, nil)
+	call(b, nil)
+	want := []string{"Closed->Open", "Open->HalfOpen", "HalfOpen->Open", "Open->HalfOpen", "HalfOpen->Closed"}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(transitions, want) {
+		t.Errorf("transitions = %v, want %v", transitions, want)
+	}
+}
+
+func TestCircuitBreaker_IgnoredOutcomes(t *testing.T) {
+	b := concurrency.NewCircuitBreaker(
+		concurrency.WithFailureThreshold(1),
+		concurrency.WithFailurePredicate(func(err error) bool { return errors.Is(err, errDown) }),
+	)
+	call(b, context.Canceled)
+	call(b, errors.New("bad request"))
+	if b.State() != concurrency.BreakerClosed {
+		t.Errorf("State() = %v, want Closed: errors rejected by the predicate must not count", b.State())
+	}
+
+	if c := b.Counts(); c.Requests != 0 {
+		t.Errorf("Counts() after ignored errors = %+v, want no requests counted", c)
+	}
+
+	// Releasing twice, or after the state changed, has no further effect.
+	release, _ := b.Acquire(context.Background())
+	call(b, errDown)
+	release(errDown)
+	release(errDown)
+	b.Reset()
+	if c := b.Counts(); c.Requests != 0 {
+		t.Errorf("Counts() after Reset = %+v, want zero", c)
+	}
+}
+
+func TestCircuitBreaker_CanceledProbe(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrency.NewCircuitBreaker(
+		concurrency.WithFailureThreshold(1),
+		concurrency.WithOpenTimeout(time.Second),
+		concurrency.WithBreakerClock(clock),
+	)
+	ctx := context.Background()
+	call(b, errDown)
+	clock.Advance(time.Second)
+
+	release, err := b.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("probe Acquire error = %v", err)
+	}
+	release(context.Canceled)
+	if b.State() != concurrency.BreakerHalfOpen {
+		t.Fatalf("State() after a canceled probe = %v, want HalfOpen", b.State())
+	}
+
+	// The canceled probe gave its slot back, so another one is admitted and
+	// decides the outcome.
+	if err := call(b, errDown); !errors.Is(err, errDown) {
+		t.Fatalf("second probe error = %v, want errDown", err)
+	}
+	if b.State() != concurrency.BreakerOpen {
+		t.Errorf("State() after a failed probe = %v, want Open", b.State())
+	}
+}
+
+func TestGuarded(t *testing.T) {
+	checkGoroutineLeaks(t)
+	breaker := concurrency.NewCircuitBreaker(concurrency.WithFailureThreshold(2))
+	bulkhead := concurrency.NewBulkhead(1)
+	calls := 0
+	fetch := concurrency.Guarded(func(ctx context.Context) (string, error) {
+		calls++
+		if calls <= 2 {
+			return "", errDown
+		}
+		return "ok", nil
+	}, breaker, bulkhead)
+
+	ctx := context.Background()
+	for range 2 {
+		if _, err := fetch(ctx); !errors.Is(err, errDown) {
+			t.Errorf("fetch() error = %v, want errDown", err)
+		}
+	}
+	if _, err := fetch(ctx); !errors.Is(err, concurrency.ErrBreakerOpen) {
+		t.Errorf("fetch() on open breaker error = %v, want ErrBreakerOpen", err)
+	}
+	if calls != 2 || bulkhead.InFlight() != 0 {
+		t.Errorf("calls = %d, bulkhead in flight = %d; want 2 and 0", calls, bulkhead.InFlight())
+	}
+
+	// A bulkhead rejection releases the breaker without counting a failure.
+	breaker.Reset()
+	release, _ := bulkhead.Acquire(ctx)
+	if _, err := fetch(ctx); !errors.Is(err, concurrency.ErrBulkheadFull) {
+		t.Errorf("fetch() with bulkhead full error = %v, want ErrBulkheadFull", err)
+	}
+	release(nil)
+	if c := breaker.Counts(); c.Requests != 0 {
+		t.Errorf("breaker counted a call that was never made: %+v", c)
+	}
+	if v, err := fetch(ctx); v != "ok" || err != nil {
+		t.Errorf("fetch() = (%q, %v), want (ok, nil)", v, err)
+	}
+}
+
+func TestGuarded_Panic(t *testing.T) {
+	b := concurrency.NewCircuitBreaker(concurrency.WithFailureThreshold(1))
+	fn := concurrency.Guarded(func(context.Context) (int, error) { panic("boom") }, b)
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the original panic", r)
+			}
+		}()
+		fn(context.Background())
+	}()
+	if b.State() != concurrency.BreakerOpen {
+		t.Errorf("State() after a panicking call = %v, want Open", b.State())
+	}
+}
+
+func TestBreakerOptions_Panics(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"WithFailureThreshold(0)":  func() { concurrency.WithFailureThreshold(0) },
+		"WithFailureRatio(1.5, 1)": func() { concurrency.WithFailureRatio(1.5, 1) },
+		"WithFailureRatio(0.5, 0)": func() { concurrency.WithFailureRatio(0.5, 0) },
+		"WithRollingWindow(0, 1)":  func() { concurrency.WithRollingWindow(0, 1) },
+		"WithOpenTimeout(-1)":      func() { concurrency.WithOpenTimeout(-1) },
+		"WithHalfOpenProbes(0)":    func() { concurrency.WithHalfOpenProbes(0) },
+		"NewBulkhead(0)":           func() { concurrency.NewBulkhead(0) },
+		"WithMaxWaiting(-1)":       func() { concurrency.WithMaxWaiting(-1) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func ExampleCircuitBreaker() {
+	breaker := concurrency.NewCircuitBreaker(concurrency.WithFailureThreshold(2))
+	lookup := concurrency.Guarded(func(context.Context) (string, error) {
+		return "", errors.New("timeout")
+	}, breaker)
+
+	for range 3 {
+		_, err := lookup(context.Background())
+		fmt.Println(err, "|", breaker.State())
+	}
+	// Output:
+	// timeout | Closed
+	// timeout | Open
+	// concurrency: circuit breaker open | Open
+}
@@ -0,0 +1,292 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// ErrFutureTimeout is the error of a future created by Timeout whose source
+// did not settle in time. It wraps context.DeadlineExceeded.
+var ErrFutureTimeout = fmt.Errorf("concurrency: future timed out: %w", context.DeadlineExceeded)
+
+// Result holds the outcome of an asynchronous operation, as reported by
+// AllSettled.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Future is the eventual result of a function started with Go. A future
+// settles exactly once, with a value or an error; its result can then be read
+// any number of times, from any goroutine.
+type Future[T any] struct {
+	done   chan struct{}
+	value  T
+	err    error
+	cancel context.CancelFunc
+}
+
+// Go starts fn on a new goroutine and returns a Future for its result.
+//
+// Type Parameters:
+//
+//	T: The result type.
+//
+// Parameters:
+//
+//	ctx: The parent of the context passed to fn.
+//	fn: The work to run. Its context is cancelled when ctx is, when the
+//	    future's Cancel is called, when a combinator that owns the future
+//	    no longer needs it, and after fn returns. A panic in fn is
+//	    recovered and settles the future with a *PanicError.
+//
+// Returns:
+//
+//	*Future[T]: A future that settles when fn returns.
+func Go[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Future[T]{done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer cancel()
+		f.value, f.err = callSafely(func() (T, error) { return fn(ctx) })
+		close(f.done)
+	}()
+	return f
+}
+
+// Done returns a channel that is closed when the future has settled.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await blocks until the future settles and returns its result, or returns
+// ctx.Err() if ctx is done first. Giving up the wait does not cancel the
+// future; call Cancel for that.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Cancel cancels the context of the future's function. The future still
+// settles, with whatever the function returns once it notices.
+func (f *Future[T]) Cancel() {
+	f.cancel()
+}
+
+// Then returns a future that, once f succeeds, runs fn on its value. If f
+// fails, the returned future fails with the same error and fn is not called.
+// Cancelling the returned future stops waiting for f but does not cancel f,
+// which may have other consumers.
+//
+// Type Parameters:
+//
+//	T: The result type of f.
+//	R: The result type of fn.
+//
+// Parameters:
+//
+//	f: The source future.
+//	fn: The continuation.
+//
+// Returns:
+//
+//	*Future[R]: A future for fn's result.
+func Then[T, R any](f *Future[T], fn func(ctx context.Context, value T) (R, error)) *Future[R] {
+	return Go(context.Background(), func(ctx context.Context) (R, error) {
+		v, err := f.Await(ctx)
+		if err != nil {
+			var zero R
+			return zero, err
+		}
+		return fn(ctx, v)
+	})
+}
+
+// Catch returns a future that settles like f when f succeeds, and otherwise
+// runs fn on f's error, for example to fall back to a default value. As with
+// Then, cancelling the returned future does not cancel f.
+//
+// Type Parameters:
+//
+//	T: The result type.
+//
+// Parameters:
+//
+//	f: The source future.
+//	fn: The recovery function; it may return a new error.
+//
+// Returns:
+//
+//	*Future[T]: A future for f's value or fn's result.
+func Catch[T any](f *Future[T], fn func(ctx context.Context, err error) (T, error)) *Future[T] {
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		v, err := f.Await(ctx)
+		if err == nil || ctx.Err() != nil {
+			return v, err
+		}
+		return fn(ctx, err)
+	})
+}
+
+// TimeoutOption configures Timeout.
+type TimeoutOption func(*timeoutConfig)
+
+type timeoutConfig struct {
+	clock clock.Clock
+}
+
+// WithTimeoutClock replaces the clock that times a Timeout, so tests can use
+// a clock.FakeClock. The default is clock.Real().
+func WithTimeoutClock(c clock.Clock) TimeoutOption {
+	return func(cfg *timeoutConfig) { cfg.clock = c }
+}
+
+// Timeout returns a future that settles like f if f settles within d, and
+// otherwise cancels f and fails with ErrFutureTimeout.
+func Timeout[T any](f *Future[T], d time.Duration, opts ...TimeoutOption) *Future[T] {
+	var cfg timeoutConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	timer := clock.OrReal(cfg.clock).NewTimer(d)
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		defer timer.Stop()
+		select {
+		case <-f.done:
+			return f.value, f.err
+		case <-timer.C():
+			f.Cancel()
+			var zero T
+			return zero, ErrFutureTimeout
+		case <-ctx.Done():
+			f.Cancel()
+			var zero T
+			return zero, ctx.Err()
+		}
+	})
+}
+
+// settled reports the indexes of futures as they settle, until ctx is done.
+// The channel is buffered for every future, so abandoning it leaks nothing.
+func settled[T any](ctx context.Context, futures []*Future[T]) <-chan int {
+	ch := make(chan int, len(futures))
+	for i, f := range futures {
+		go func() {
+			select {
+			case <-f.done:
+				ch <- i
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ch
+}
+
+func cancelAll[T any](futures []*Future[T]) {
+	for _, f := range futures {
+		f.Cancel()
+	}
+}
+
+// AllOf returns a future that succeeds with the values of all futures, in
+// argument order, once every one has succeeded. It fails with the first error
+// to occur and cancels the remaining futures. Cancelling the returned future
+// cancels every input future. With no futures, it succeeds with an empty
+// slice.
+func AllOf[T any](futures ...*Future[T]) *Future[[]T] {
+	return Go(context.Background(), func(ctx context.Context) ([]T, error) {
+		values := make([]T, len(futures))
+		ch := settled(ctx, futures)
+		for range futures {
+			select {
+			case i := <-ch:
+				if err := futures[i].err; err != nil {
+					cancelAll(futures)
+					return nil, err
+				}
+				values[i] = futures[i].value
+			case <-ctx.Done():
+				cancelAll(futures)
+				return nil, ctx.Err()
+			}
+		}
+		return values, nil
+	})
+}
+
+// AnyOf returns a future that succeeds with the value of the first future to
+// succeed and cancels the others. If every future fails, it fails with all of
+// their errors joined in argument order. Cancelling the returned future
+// cancels every input future. It panics if no futures are given.
+func AnyOf[T any](futures ...*Future[T]) *Future[T] {
+	if len(futures) == 0 {
+		panic("concurrency.AnyOf: no futures")
+	}
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		defer cancelAll(futures)
+		errs := make([]error, len(futures))
+		ch := settled(ctx, futures)
+		for range futures {
+			select {
+			case i := <-ch:
+				if futures[i].err == nil {
+					return futures[i].value, nil
+				}
+				errs[i] = futures[i].err
+			case <-ctx.Done():
+				var zero T
+				return zero, ctx.Err()
+			}
+		}
+		var zero T
+		return zero, errors.Join(errs...)
+	})
+}
+
+// Race returns a future that settles like the first of futures to settle,
+// whether it succeeded or failed, and cancels the others. Cancelling the
+// returned future cancels every input future. It panics if no futures are
+// given.
+func Race[T any](futures ...*Future[T]) *Future[T] {
+	if len(futures) == 0 {
+		panic("concurrency.Race: no futures")
+	}
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		defer cancelAll(futures)
+		select {
+		case i := <-settled(ctx, futures):
+			return futures[i].value, futures[i].err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	})
+}
+
+// AllSettled returns a future that succeeds, once every future has settled,
+// with each one's Result in argument order. It never fails on account of the
+// inputs; it fails only if it is cancelled, which also cancels every input
+// future.
+func AllSettled[T any](futures ...*Future[T]) *Future[[]Result[T]] {
+	return Go(context.Background(), func(ctx context.Context) ([]Result[T], error) {
+		results := make([]Result[T], len(futures))
+		for i, f := range futures {
+			v, err := f.Await(ctx)
+			if ctx.Err() != nil {
+				cancelAll(futures)
+				return nil, ctx.Err()
+			}
+			results[i] = Result[T]{Value: v, Err: err}
+		}
+		return results, nil
+	})
+}
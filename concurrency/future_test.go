@@ -0,0 +1,258 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+// after returns a future that settles with v and err after d, or with
+// ctx.Err() if it is cancelled first.
+func after[T any](d time.Duration, v T, err error) *concurrency.Future[T] {
+	return concurrency.Go(context.Background(), func(ctx context.Context) (T, error) {
+		select {
+		case <-time.After(d):
+			return v, err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	})
+}
+
+// blocked returns a future that only settles when cancelled, and a channel
+// closed once that happens.
+func blocked[T any]() (*concurrency.Future[T], <-chan struct{}) {
+	cancelled := make(chan struct{})
+	f := concurrency.Go(context.Background(), func(ctx context.Context) (T, error) {
+		<-ctx.Done()
+		close(cancelled)
+		var zero T
+		return zero, ctx.Err()
+	})
+	return f, cancelled
+}
+
+func waitClosed(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s was not cancelled", what)
+	}
+}
+
+func TestFuture_GoAwait(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	f := concurrency.Go(ctx, func(context.Context) (int, error) { return 7, nil })
+	if v, err := f.Await(ctx); v != 7 || err != nil {
+		t.Errorf("Await() = (%d, %v), want (7, nil)", v, err)
+	}
+	// A settled future can be awaited again.
+	if v, _ := f.Await(ctx); v != 7 {
+		t.Errorf("second Await() = %d, want 7", v)
+	}
+
+	p := concurrency.Go(ctx, func(context.Context) (int, error) { panic("oops") })
+	var pe *concurrency.PanicError
+	if _, err := p.Await(ctx); !errors.As(err, &pe) {
+		t.Errorf("Await() on panicking future = %v, want PanicError", err)
+	}
+
+	slow, cancelled := blocked[int]()
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := slow.Await(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Await() with a short ctx = %v, want DeadlineExceeded", err)
+	}
+	select {
+	case <-cancelled:
+		t.Error("giving up Await must not cancel the future")
+	default:
+	}
+	slow.Cancel()
+	waitClosed(t, cancelled, "future")
+	if _, err := slow.Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Await() after Cancel = %v, want context.Canceled", err)
+	}
+}
+
+func TestFuture_ThenCatch(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	errParse := errors.New("parse")
+
+	n := concurrency.Go(ctx, func(context.Context) (string, error) { return "21", nil })
+	doubled := concurrency.Then(n, func(_ context.Context, s string) (int, error) {
+		v, err := strconv.Atoi(s)
+		return v * 2, err
+	})
+	if v, err := doubled.Await(ctx); v != 42 || err != nil {
+		t.Errorf("Then() = (%d, %v), want (42, nil)", v, err)
+	}
+
+	called := false
+	failed := concurrency.Then(concurrency.Go(ctx, func(context.Context) (string, error) { return "", errParse }),
+		func(context.Context, string) (int, error) { called = true; return 0, nil })
+	if _, err := failed.Await(ctx); err != errParse || called {
+		t.Errorf("Then() on failure = %v (called=%v), want errParse without calling fn", err, called)
+	}
+
+	recovered := concurrency.Catch(failed, func(_ context.Context, err error) (int, error) {
+		if errors.Is(err, errParse) {
+			return -1, nil
+		}
+		return 0, err
+	})
+	if v, err := recovered.Await(ctx); v != -1 || err != nil {
+		t.Errorf("Catch() = (%d, %v), want (-1, nil)", v, err)
+	}
+	if v, _ := concurrency.Catch(doubled, func(context.Context, error) (int, error) { return 0, nil }).Await(ctx); v != 42 {
+		t.Errorf("Catch() on success = %d, want 42", v)
+	}
+}
+
+func TestFuture_Timeout(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	slow, cancelled := blocked[int]()
+	_, err := concurrency.Timeout(slow, 10*time.Millisecond).Await(ctx)
+	if !errors.Is(err, concurrency.ErrFutureTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Timeout() = %v, want ErrFutureTimeout", err)
+	}
+	waitClosed(t, cancelled, "timed-out future")
+
+	fast := after(0, "ok", nil)
+	if v, err := concurrency.Timeout(fast, time.Second).Await(ctx); v != "ok" || err != nil {
+		t.Errorf("Timeout() on a fast future = (%q, %v)", v, err)
+	}
+}
+
+func TestFuture_TimeoutFakeClock(t *testing.T) {
+	checkGoroutineLeaks(t)
+	clock := newFakeClock()
+	slow, cancelled := blocked[int]()
+	f := concurrency.Timeout(slow, time.Minute, concurrency.WithTimeoutClock(clock))
+
+	clock.BlockUntil(1)
+	// The fake clock fires timers within Advance, so nothing can be late.
+	clock.Advance(59 * time.Second)
+	select {
+	case <-f.Done():
+		t.Fatal("Timeout() settled before the deadline")
+	default:
+	}
+	clock.Advance(time.Second)
+	if _, err := f.Await(context.Background()); !errors.Is(err, concurrency.ErrFutureTimeout) {
+		t.Errorf("Timeout() = %v, want ErrFutureTimeout", err)
+	}
+	waitClosed(t, cancelled, "timed-out future")
+}
+
+func TestAllOf(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	all := concurrency.AllOf(after(20*time.Millisecond, 1, nil), after(0, 2, nil), after(10*time.Millisecond, 3, nil))
+	if v, err := all.Await(ctx); !reflect.DeepEqual(v, []int{1, 2, 3}) || err != nil {
+		t.Errorf("AllOf() = (%v, %v), want ([1 2 3], nil)", v, err)
+	}
+
+	slow, cancelled := blocked[int]()
+	_, err := concurrency.AllOf(slow, after(0, 0, errFlaky)).Await(ctx)
+	if err != errFlaky {
+		t.Errorf("AllOf() error = %v, want errFlaky", err)
+	}
+	waitClosed(t, cancelled, "remaining AllOf input")
+
+	if v, err := concurrency.AllOf[int]().Await(ctx); v == nil || len(v) != 0 || err != nil {
+		t.Errorf("AllOf() with no futures = (%#v, %v), want an empty slice", v, err)
+	}
+}
+
+func TestAnyOfAndRace(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	errA, errB := errors.New("a"), errors.New("b")
+
+	slow, cancelled := blocked[string]()
+	first := concurrency.AnyOf(after(0, "", errA), after(10*time.Millisecond, "second", nil), slow)
+	if v, err := first.Await(ctx); v != "second" || err != nil {
+		t.Errorf("AnyOf() = (%q, %v), want the first success", v, err)
+	}
+	waitClosed(t, cancelled, "AnyOf loser")
+
+	_, err := concurrency.AnyOf(after(10*time.Millisecond, "", errA), after(0, "", errB)).Await(ctx)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) || err.Error() != "a\nb" {
+		t.Errorf("AnyOf() all failing = %q, want both errors in argument order", err)
+	}
+
+	slow, cancelled = blocked[string]()
+	if _, err := concurrency.Race(after(0, "", errA), slow).Await(ctx); err != errA {
+		t.Errorf("Race() = %v, want the first settlement even if it failed", err)
+	}
+	waitClosed(t, cancelled, "Race loser")
+
+	for name, fn := range map[string]func(){
+		"AnyOf": func() { concurrency.AnyOf[int]() },
+		"Race":  func() { concurrency.Race[int]() },
+	} {
+		t.Run(name+"Empty", func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s() with no futures did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func TestAllSettled(t *testing.T) {
+	checkGoroutineLeaks(t)
+	got, err := concurrency.AllSettled(after(10*time.Millisecond, 1, nil), after(0, 0, errFlaky)).Await(context.Background())
+	want := []concurrency.Result[int]{{Value: 1}, {Err: errFlaky}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("AllSettled() = (%v, %v), want (%v, nil)", got, err, want)
+	}
+}
+
+func TestCombinators_CancelPropagatesToChildren(t *testing.T) {
+	checkGoroutineLeaks(t)
+	combine := map[string]func(a, b *concurrency.Future[int]) func(){
+		"AllOf":      func(a, b *concurrency.Future[int]) func() { return concurrency.AllOf(a, b).Cancel },
+		"AnyOf":      func(a, b *concurrency.Future[int]) func() { return concurrency.AnyOf(a, b).Cancel },
+		"Race":       func(a, b *concurrency.Future[int]) func() { return concurrency.Race(a, b).Cancel },
+		"AllSettled": func(a, b *concurrency.Future[int]) func() { return concurrency.AllSettled(a, b).Cancel },
+	}
+	for name, build := range combine {
+		t.Run(name, func(t *testing.T) {
+			a, aCancelled := blocked[int]()
+			b, bCancelled := blocked[int]()
+			cancel := build(a, b)
+			cancel()
+			waitClosed(t, aCancelled, name+" child a")
+			waitClosed(t, bCancelled, name+" child b")
+		})
+	}
+}
+
+func ExampleAllOf() {
+	ctx := context.Background()
+	fetch := func(id int) *concurrency.Future[string] {
+		return concurrency.Go(ctx, func(context.Context) (string, error) {
+			return fmt.Sprintf("user-%d", id), nil
+		})
+	}
+
+	users, err := concurrency.AllOf(fetch(1), fetch(2), fetch(3)).Await(ctx)
+	fmt.Println(users, err)
+	// Output:
+	// [user-1 user-2 user-3] <nil>
+}
@@ -0,0 +1,72 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+)
+
+// ErrCallNotMade is passed to a guard's release function when the call it
+// admitted was never made, because a later guard rejected it. Guards must
+// not count it as a failure.
+var ErrCallNotMade = errors.New("concurrency: call not made")
+
+// Guard admits or rejects calls to a protected operation. CircuitBreaker and
+// Bulkhead are guards; Guarded wraps a function in any number of them.
+type Guard interface {
+	// Acquire asks to make one call. On success, the caller must make the
+	// call and then invoke release exactly once with its error, nil on
+	// success, or ErrCallNotMade if it decided not to call after all. On
+	// failure, the call must not be made.
+	Acquire(ctx context.Context) (release func(err error), err error)
+}
+
+// Guarded returns a function that calls fn only when every guard admits the
+// call. Guards are acquired in order, so the first guard is the outermost:
+// with Guarded(fn, breaker, bulkhead) an open breaker rejects calls before
+// they take a bulkhead slot.
+//
+// Type Parameters:
+//
+//	T: The result type of fn.
+//
+// Parameters:
+//
+//	fn: The protected operation.
+//	guards: The guards to pass, outermost first.
+//
+// Returns:
+//
+//	func(ctx context.Context) (T, error): Calls fn, or fails with the error
+//	    of the first guard that rejects the call. A panic in fn is reported
+//	    to the guards as a *PanicError and then re-raised.
+func Guarded[T any](fn func(ctx context.Context) (T, error), guards ...Guard) func(ctx context.Context) (T, error) {
+	guards = append([]Guard(nil), guards...)
+	return func(ctx context.Context) (T, error) {
+		releases := make([]func(error), 0, len(guards))
+		releaseAll := func(err error) {
+			for i := len(releases) - 1; i >= 0; i-- {
+				releases[i](err)
+			}
+		}
+		for _, g := range guards {
+			release, err := g.Acquire(ctx)
+			if err != nil {
+				releaseAll(ErrCallNotMade)
+				var zero T
+				return zero, err
+			}
+			releases = append(releases, release)
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				releaseAll(&PanicError{Value: r, Stack: debug.Stack()})
+				panic(r)
+			}
+		}()
+		v, err := fn(ctx)
+		releaseAll(err)
+		return v, err
+	}
+}
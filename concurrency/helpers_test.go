@@ -0,0 +1,54 @@
+package concurrency_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// --- Shared Test Helper Functions ---
+
+// checkGoroutineLeaks records the current number of goroutines and, when the
+// test finishes, fails it if more are still running after a grace period.
+// Call it first in a test so its cleanup runs after the test's own cleanups.
+// Tests using it must not run in parallel.
+func checkGoroutineLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			after := runtime.NumGoroutine()
+			if after <= before {
+				return
+			}
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<16)
+				buf = buf[:runtime.Stack(buf, true)]
+				t.Errorf("goroutine leak: %d goroutines before, %d after\n%s", before, after, buf)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
+
+// eventually polls cond until it is true or the timeout expires.
+func eventually(t *testing.T, timeout time.Duration, cond func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within %v: %s", timeout, msg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// newFakeClock returns a clock.FakeClock set to a fixed date, for components
+// that accept a clock.
+func newFakeClock() *clock.FakeClock {
+	return clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,42 @@
+package concurrency
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error reported in place of a panic raised by a
+// user-supplied function, so that one bad job fails on its own instead of
+// crashing the process.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine, as captured by
+	// runtime/debug.Stack at the point of recovery.
+	Stack []byte
+}
+
+// Error returns the panic value. The stack trace is not included; read the
+// Stack field for it.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("concurrency: recovered panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and
+// errors.As see through a panic(err).
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// callSafely calls fn and converts a panic into a *PanicError.
+func callSafely[T any](fn func() (T, error)) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
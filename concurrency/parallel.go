@@ -0,0 +1,206 @@
+package concurrency
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+// ParallelOption configures the parallel slice functions.
+type ParallelOption func(*parallelConfig)
+
+type parallelConfig struct {
+	workers   int
+	chunkSize int
+	threshold int
+}
+
+// WithParallelism sets the number of goroutines that share the work. It
+// panics if n is not positive. The default is GOMAXPROCS.
+func WithParallelism(n int) ParallelOption {
+	if n <= 0 {
+		panic("concurrency.WithParallelism: n must be positive")
+	}
+	return func(c *parallelConfig) { c.workers = n }
+}
+
+// WithChunkSize sets how many elements each unit of work covers. Smaller
+// chunks balance uneven work better; larger chunks cost less coordination.
+// It panics if n is not positive. By default the input is split into about
+// four chunks per worker, of at least 1024 elements each.
+func WithChunkSize(n int) ParallelOption {
+	if n <= 0 {
+		panic("concurrency.WithChunkSize: n must be positive")
+	}
+	return func(c *parallelConfig) { c.chunkSize = n }
+}
+
+// WithSequentialThreshold sets the input size up to which ParallelSortFunc
+// and ParallelUnique run sequentially, because below it the cost of starting
+// goroutines outweighs the gain. ParallelSortFunc also stops splitting the
+// input into parallel subtasks at this size. It panics if n is not positive.
+// The default is 8192.
+func WithSequentialThreshold(n int) ParallelOption {
+	if n <= 0 {
+		panic("concurrency.WithSequentialThreshold: n must be positive")
+	}
+	return func(c *parallelConfig) { c.threshold = n }
+}
+
+func newParallelConfig(n int, opts []ParallelOption) parallelConfig {
+	cfg := parallelConfig{workers: runtime.GOMAXPROCS(0), threshold: 1 << 13}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.chunkSize == 0 {
+		cfg.chunkSize = max((n+4*cfg.workers-1)/(4*cfg.workers), 1024)
+	}
+	return cfg
+}
+
+// parallelFor calls fn for every index in [0, n) on up to workers
+// goroutines and waits for them. If any call panics, the remaining indexes
+// are skipped and the first panic is re-raised in the caller as a
+// *PanicError; a *PanicError raised by a nested parallelFor is passed on
+// unchanged.
+func parallelFor(n, workers int, fn func(i int)) {
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		panicked atomic.Pointer[PanicError]
+	)
+	work := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				pe, ok := r.(*PanicError)
+				if !ok {
+					pe = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+				panicked.CompareAndSwap(nil, pe)
+			}
+		}()
+		for panicked.Load() == nil {
+			i := int(next.Add(1) - 1)
+			if i >= n {
+				return
+			}
+			fn(i)
+		}
+	}
+	if workers = min(workers, n); workers <= 1 {
+		work()
+	} else {
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				work()
+			}()
+		}
+		wg.Wait()
+	}
+	if pe := panicked.Load(); pe != nil {
+		panic(pe)
+	}
+}
+
+// ParallelReduce is the parallel counterpart of functional.Reduce. It splits
+// xs into chunks with functional.Chunk, reduces the chunks concurrently, each
+// starting from identity, and then combines the partial results pairwise in
+// a tree that keeps chunk order. The result is therefore deterministic and
+// correct for any associative combiner, even a non-commutative one such as
+// string concatenation.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	U: The type of the result.
+//
+// Parameters:
+//
+//	xs: The slice to reduce. Can be nil or empty.
+//	identity: The starting value of every chunk. It must be an identity of
+//	          combiner (0 for a sum, "" for concatenation). It is shared by
+//	          all chunks, so reducer must not modify it in place; for maps,
+//	          pass nil and allocate on first write.
+//	reducer: Folds one element into a chunk's partial result.
+//	combiner: Merges two adjacent partial results, left before right. It
+//	          must be associative.
+//	opts: Optional WithParallelism and WithChunkSize settings.
+//
+// Returns:
+//
+//	U: The combined result, or identity if xs is empty.
+//
+// A panic in reducer or combiner is re-raised in the calling goroutine as a
+// *PanicError.
+func ParallelReduce[T, U any](xs []T, identity U, reducer func(acc U, element T) U, combiner func(left, right U) U, opts ...ParallelOption) U {
+	if len(xs) == 0 {
+		return identity
+	}
+	cfg := newParallelConfig(len(xs), opts)
+	chunks := functional.Chunk(xs, cfg.chunkSize)
+	partials := make([]U, len(chunks))
+	parallelFor(len(chunks), cfg.workers, func(i int) {
+		partials[i] = functional.Reduce(chunks[i], identity, reducer)
+	})
+
+	// Combine neighbours level by level: (0,1), (2,3), ... until one is left.
+	for len(partials) > 1 {
+		next := make([]U, (len(partials)+1)/2)
+		parallelFor(len(partials)/2, cfg.workers, func(i int) {
+			next[i] = combiner(partials[2*i], partials[2*i+1])
+		})
+		if len(partials)%2 == 1 {
+			next[len(next)-1] = partials[len(partials)-1]
+		}
+		partials = next
+	}
+	return partials[0]
+}
+
+// ParallelGroupBy is the parallel counterpart of functional.GroupBy. Each
+// chunk of xs is grouped into its own map concurrently, and the maps are then
+// merged in chunk order, so the elements of each group keep their input
+// order exactly as with GroupBy.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The group key type.
+//
+// Parameters:
+//
+//	xs: The slice to group. Can be nil or empty.
+//	classifier: Returns the group of an element. It is called concurrently.
+//	opts: Optional WithParallelism and WithChunkSize settings.
+//
+// Returns:
+//
+//	map[K][]T: The elements of each group, in input order. An empty,
+//	           non-nil map if xs is empty.
+//
+// A panic in classifier is re-raised in the calling goroutine as a
+// *PanicError.
+func ParallelGroupBy[T any, K comparable](xs []T, classifier func(element T) K, opts ...ParallelOption) map[K][]T {
+	if len(xs) == 0 {
+		return make(map[K][]T)
+	}
+	cfg := newParallelConfig(len(xs), opts)
+	chunks := functional.Chunk(xs, cfg.chunkSize)
+	parts := make([]map[K][]T, len(chunks))
+	parallelFor(len(chunks), cfg.workers, func(i int) {
+		parts[i] = functional.GroupBy(chunks[i], classifier)
+	})
+
+	result := parts[0]
+	for _, part := range parts[1:] {
+		for k, group := range part {
+			result[k] = append(result[k], group...)
+		}
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package concurrency
+
+import (
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+// ParallelSortFunc sorts xs in place in ascending order as determined by cmp,
+// like slices.SortFunc, using a parallel merge sort. The input is split in
+// halves that are sorted concurrently and then merged, with the merges
+// themselves split in parallel; sub-slices of at most the sequential
+// threshold are sorted with slices.SortFunc. The sort is not guaranteed to be
+// stable.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	xs: The slice to sort. Can be nil or empty.
+//	cmp: Returns a negative number when a < b, a positive number when
+//	     a > b and zero when a == b. It is called concurrently.
+//	opts: Optional WithParallelism and WithSequentialThreshold settings.
+//
+// The sort needs a scratch buffer as large as xs. A panic in cmp is re-raised
+// in the calling goroutine as a *PanicError, leaving xs partially sorted.
+func ParallelSortFunc[T any](xs []T, cmp func(a, b T) int, opts ...ParallelOption) {
+	cfg := newParallelConfig(len(xs), opts)
+	if len(xs) <= cfg.threshold || cfg.workers == 1 {
+		slices.SortFunc(xs, cmp)
+		return
+	}
+	s := mergeSorter[T]{cmp: cmp, threshold: cfg.threshold}
+	// Two levels of splitting beyond one task per worker even out the load.
+	depth := bits.Len(uint(cfg.workers-1)) + 2
+	s.sort(xs, make([]T, len(xs)), depth)
+}
+
+type mergeSorter[T any] struct {
+	cmp       func(a, b T) int
+	threshold int
+}
+
+// sort sorts xs using buf, which has the same length, as scratch space.
+// depth is how many more times the work may be split in two.
+func (s *mergeSorter[T]) sort(xs, buf []T, depth int) {
+	if len(xs) <= s.threshold || depth == 0 {
+		slices.SortFunc(xs, s.cmp)
+		return
+	}
+	mid := len(xs) / 2
+	parallelFor(2, 2, func(i int) {
+		if i == 0 {
+			s.sort(xs[:mid], buf[:mid], depth-1)
+		} else {
+			s.sort(xs[mid:], buf[mid:], depth-1)
+		}
+	})
+	s.merge(xs[:mid], xs[mid:], buf, depth)
+	copy(xs, buf)
+}
+
+// merge merges the sorted slices a and b into dst, keeping elements of a
+// before equal elements of b. Large merges are split around the middle
+// element of the longer input and the halves merged concurrently.
+func (s *mergeSorter[T]) merge(a, b, dst []T, depth int) {
+	if len(a)+len(b) <= s.threshold || depth == 0 {
+		s.mergeSequential(a, b, dst)
+		return
+	}
+	var i, j int // a[:i] and b[:j] go before the pivot, the rest after
+	if len(a) >= len(b) {
+		i = len(a) / 2
+		j, _ = slices.BinarySearchFunc(b, a[i], s.cmp) // b's elements < a[i]
+		dst[i+j] = a[i]
+		parallelFor(2, 2, func(half int) {
+			if half == 0 {
+				s.merge(a[:i], b[:j], dst[:i+j], depth-1)
+			} else {
+				s.merge(a[i+1:], b[j:], dst[i+j+1:], depth-1)
+			}
+		})
+		return
+	}
+	j = len(b) / 2
+	i = sort.Search(len(a), func(k int) bool { return s.cmp(a[k], b[j]) > 0 }) // a's elements <= b[j]
+	dst[i+j] = b[j]
+	parallelFor(2, 2, func(half int) {
+		if half == 0 {
+			s.merge(a[:i], b[:j], dst[:i+j], depth-1)
+		} else {
+			s.merge(a[i:], b[j+1:], dst[i+j+1:], depth-1)
+		}
+	})
+}
+
+func (s *mergeSorter[T]) mergeSequential(a, b, dst []T) {
+	k := 0
+	for len(a) > 0 && len(b) > 0 {
+		if s.cmp(b[0], a[0]) < 0 {
+			dst[k] = b[0]
+			b = b[1:]
+		} else {
+			dst[k] = a[0]
+			a = a[1:]
+		}
+		k++
+	}
+	k += copy(dst[k:], a)
+	copy(dst[k:], b)
+}
@@ -0,0 +1,121 @@
+package concurrency_test
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+func randomInts(n, limit int) []int {
+	r := rand.New(rand.NewPCG(uint64(n), uint64(limit)))
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = r.IntN(limit)
+	}
+	return xs
+}
+
+func TestParallelSortFunc(t *testing.T) {
+	tests := []struct {
+		name string
+		xs   []int
+		opts []concurrency.ParallelOption
+	}{
+		{"nil", nil, nil},
+		{"below threshold", randomInts(100, 1000), nil},
+		{"parallel", randomInts(10_000, 1_000_000), []concurrency.ParallelOption{concurrency.WithParallelism(4), concurrency.WithSequentialThreshold(64)}},
+		{"many duplicates", randomInts(5_000, 10), []concurrency.ParallelOption{concurrency.WithParallelism(8), concurrency.WithSequentialThreshold(16)}},
+		{"already sorted", ints(3_000), []concurrency.ParallelOption{concurrency.WithParallelism(3), concurrency.WithSequentialThreshold(32)}},
+		{"odd length", randomInts(1_001, 50), []concurrency.ParallelOption{concurrency.WithParallelism(2), concurrency.WithSequentialThreshold(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := slices.Clone(tt.xs)
+			slices.Sort(want)
+			got := slices.Clone(tt.xs)
+			concurrency.ParallelSortFunc(got, cmp.Compare[int], tt.opts...)
+			if !slices.Equal(got, want) {
+				t.Errorf("ParallelSortFunc() result is not sorted or lost elements")
+			}
+		})
+	}
+}
+
+func TestParallelSortFunc_Descending(t *testing.T) {
+	xs := randomInts(2_000, 500)
+	concurrency.ParallelSortFunc(xs, func(a, b int) int { return cmp.Compare(b, a) },
+		concurrency.WithParallelism(4), concurrency.WithSequentialThreshold(50))
+	if !slices.IsSortedFunc(xs, func(a, b int) int { return cmp.Compare(b, a) }) {
+		t.Error("ParallelSortFunc() with a descending cmp did not sort descending")
+	}
+}
+
+func TestParallelSortFunc_Panic(t *testing.T) {
+	errCmp := errors.New("cmp failed")
+	defer func() {
+		pe, ok := recover().(*concurrency.PanicError)
+		if !ok || !errors.Is(pe, errCmp) {
+			t.Errorf("recovered %v, want a *PanicError wrapping errCmp", pe)
+		}
+	}()
+	concurrency.ParallelSortFunc(randomInts(1_000, 100), func(a, b int) int {
+		if a == 42 || b == 42 {
+			panic(errCmp)
+		}
+		return cmp.Compare(a, b)
+	}, concurrency.WithParallelism(4), concurrency.WithSequentialThreshold(10))
+	t.Error("ParallelSortFunc() did not panic")
+}
+
+func ExampleParallelSortFunc() {
+	words := []string{"pear", "fig", "banana", "kiwi", "apple"}
+	concurrency.ParallelSortFunc(words, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+	fmt.Println(words)
+	// Output:
+	// [fig kiwi pear apple banana]
+}
+
+// BenchmarkSort compares slices.SortFunc with ParallelSortFunc across input
+// sizes; on a multi-core machine the parallel sort overtakes the sequential
+// one somewhere around the default sequential threshold.
+func BenchmarkSort(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		input := randomInts(n, n)
+		xs := make([]int, n)
+		b.Run(fmt.Sprintf("SortFunc/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(xs, input)
+				slices.SortFunc(xs, cmp.Compare[int])
+			}
+		})
+		b.Run(fmt.Sprintf("ParallelSortFunc/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(xs, input)
+				concurrency.ParallelSortFunc(xs, cmp.Compare[int])
+			}
+		})
+	}
+}
+
+// BenchmarkParallelSortFunc_Threshold shows how the sequential threshold
+// trades goroutine overhead against parallelism for a fixed input size.
+func BenchmarkParallelSortFunc_Threshold(b *testing.B) {
+	const n = 1_000_000
+	input := randomInts(n, n)
+	xs := make([]int, n)
+	for _, threshold := range []int{1 << 10, 1 << 13, 1 << 16, 1 << 19} {
+		b.Run(fmt.Sprintf("threshold=%d", threshold), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(xs, input)
+				concurrency.ParallelSortFunc(xs, cmp.Compare[int], concurrency.WithSequentialThreshold(threshold))
+			}
+		})
+	}
+}
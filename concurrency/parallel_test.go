@@ -0,0 +1,184 @@
+package concurrency_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+func ints(n int) []int {
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = i
+	}
+	return xs
+}
+
+func TestParallelReduce(t *testing.T) {
+	add := func(a, b int) int { return a + b }
+	tests := []struct {
+		name string
+		n    int
+		opts []concurrency.ParallelOption
+	}{
+		{"empty", 0, nil},
+		{"single chunk", 10, nil},
+		{"default chunking", 100_000, nil},
+		{"odd chunk count", 1001, []concurrency.ParallelOption{concurrency.WithChunkSize(10), concurrency.WithParallelism(3)}},
+		{"one worker", 500, []concurrency.ParallelOption{concurrency.WithChunkSize(7), concurrency.WithParallelism(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs := ints(tt.n)
+			got := concurrency.ParallelReduce(xs, 0, add, add, tt.opts...)
+			if want := functional.Reduce(xs, 0, add); got != want {
+				t.Errorf("ParallelReduce() = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestParallelReduce_NonCommutative(t *testing.T) {
+	words := make([]string, 1000)
+	for i := range words {
+		words[i] = fmt.Sprint(i % 10)
+	}
+	concat := func(acc, s string) string { return acc + s }
+	for range 20 {
+		got := concurrency.ParallelReduce(words, "", concat, concat, concurrency.WithChunkSize(13), concurrency.WithParallelism(8))
+		if want := strings.Join(words, ""); got != want {
+			t.Fatalf("ParallelReduce() concatenation out of order:\n got %.40s...\nwant %.40s...", got, want)
+		}
+	}
+}
+
+func TestParallelReduce_MergeMaps(t *testing.T) {
+	xs := []string{"a", "b", "a", "c", "b", "a", "d"}
+	count := func(acc map[string]int, s string) map[string]int {
+		if acc == nil {
+			acc = make(map[string]int)
+		}
+		acc[s]++
+		return acc
+	}
+	merge := func(left, right map[string]int) map[string]int {
+		for k, v := range right {
+			left[k] += v
+		}
+		return left
+	}
+	got := concurrency.ParallelReduce(xs, nil, count, merge, concurrency.WithChunkSize(2))
+	want := map[string]int{"a": 3, "b": 2, "c": 1, "d": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParallelReduce() word counts = %v, want %v", got, want)
+	}
+}
+
+func TestParallelReduce_Panic(t *testing.T) {
+	errBad := errors.New("bad element")
+	defer func() {
+		pe, ok := recover().(*concurrency.PanicError)
+		if !ok || !errors.Is(pe, errBad) {
+			t.Errorf("recovered %v, want a *PanicError wrapping errBad", pe)
+		}
+	}()
+	concurrency.ParallelReduce(ints(100), 0, func(acc, x int) int {
+		if x == 42 {
+			panic(errBad)
+		}
+		return acc + x
+	}, func(a, b int) int { return a + b }, concurrency.WithChunkSize(10))
+	t.Error("ParallelReduce() did not panic")
+}
+
+func TestParallelGroupBy(t *testing.T) {
+	classifier := func(x int) string {
+		switch {
+		case x%15 == 0:
+			return "fizzbuzz"
+		case x%3 == 0:
+			return "fizz"
+		case x%5 == 0:
+			return "buzz"
+		}
+		return "other"
+	}
+	tests := []struct {
+		name string
+		xs   []int
+	}{
+		{"nil", nil},
+		{"small", ints(20)},
+		{"many chunks", ints(10_000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := concurrency.ParallelGroupBy(tt.xs, classifier, concurrency.WithChunkSize(64))
+			if want := functional.GroupBy(tt.xs, classifier); !reflect.DeepEqual(got, want) {
+				t.Errorf("ParallelGroupBy() differs from GroupBy(): got %d groups, want %d", len(got), len(want))
+			}
+		})
+	}
+}
+
+func TestParallelOptions_Panics(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"WithParallelism(0)": func() { concurrency.WithParallelism(0) },
+		"WithChunkSize(-1)":  func() { concurrency.WithChunkSize(-1) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func ExampleParallelReduce() {
+	xs := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	sum := concurrency.ParallelReduce(xs, 0,
+		func(acc, x int) int { return acc + x*x },
+		func(a, b int) int { return a + b },
+		concurrency.WithChunkSize(3))
+	fmt.Println(sum)
+	// Output:
+	// 385
+}
+
+func BenchmarkReduce_Sum(b *testing.B) {
+	xs := ints(1 << 22)
+	add := func(a, x int) int { return a + x }
+	b.Run("Reduce", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			functional.Reduce(xs, 0, add)
+		}
+	})
+	b.Run("ParallelReduce", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			concurrency.ParallelReduce(xs, 0, add, add)
+		}
+	})
+}
+
+func BenchmarkGroupBy_Mod(b *testing.B) {
+	xs := ints(1 << 20)
+	classifier := func(x int) int { return x % 64 }
+	b.Run("GroupBy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			functional.GroupBy(xs, classifier)
+		}
+	})
+	b.Run("ParallelGroupBy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			concurrency.ParallelGroupBy(xs, classifier)
+		}
+	})
+}
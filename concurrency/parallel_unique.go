@@ -0,0 +1,91 @@
+package concurrency
+
+import (
+	"hash/maphash"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+// ParallelUnique is the parallel counterpart of functional.Unique: it returns
+// the distinct elements of xs in order of first appearance. Elements are
+// assigned to partitions by hash, so equal elements always meet in the same
+// partition; each partition then finds the first occurrence of its elements
+// independently, and the survivors are gathered in input order.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice. Must be comparable.
+//
+// Parameters:
+//
+//	xs: The input slice, which may contain duplicates. It is not modified.
+//	opts: Optional WithParallelism, WithChunkSize and
+//	      WithSequentialThreshold settings.
+//
+// Returns:
+//
+//	[]T: A new slice with duplicates removed. Returns an empty slice if the
+//	     input is nil or empty.
+func ParallelUnique[T comparable](xs []T, opts ...ParallelOption) []T {
+	cfg := newParallelConfig(len(xs), opts)
+	if len(xs) <= cfg.threshold || cfg.workers == 1 {
+		return functional.Unique(xs)
+	}
+	partitions := min(cfg.workers, 256)
+	chunks := (len(xs) + cfg.chunkSize - 1) / cfg.chunkSize
+	bounds := func(c int) (int, int) {
+		return c * cfg.chunkSize, min((c+1)*cfg.chunkSize, len(xs))
+	}
+
+	// Assign every element to a partition.
+	seed := maphash.MakeSeed()
+	owner := make([]uint8, len(xs))
+	parallelFor(chunks, cfg.workers, func(c int) {
+		lo, hi := bounds(c)
+		for i := lo; i < hi; i++ {
+			owner[i] = uint8(maphash.Comparable(seed, xs[i]) % uint64(partitions))
+		}
+	})
+
+	// Each partition marks the first occurrence of each of its elements.
+	keep := make([]bool, len(xs))
+	parallelFor(partitions, cfg.workers, func(p int) {
+		seen := make(map[T]struct{}, len(xs)/partitions)
+		for i, o := range owner {
+			if int(o) != p {
+				continue
+			}
+			if _, ok := seen[xs[i]]; !ok {
+				seen[xs[i]] = struct{}{}
+				keep[i] = true
+			}
+		}
+	})
+
+	// Gather the kept elements in input order: count per chunk, then copy
+	// each chunk's survivors to its offset.
+	offsets := make([]int, chunks+1)
+	parallelFor(chunks, cfg.workers, func(c int) {
+		lo, hi := bounds(c)
+		for i := lo; i < hi; i++ {
+			if keep[i] {
+				offsets[c+1]++
+			}
+		}
+	})
+	for c := range chunks {
+		offsets[c+1] += offsets[c]
+	}
+	result := make([]T, offsets[chunks])
+	parallelFor(chunks, cfg.workers, func(c int) {
+		lo, hi := bounds(c)
+		k := offsets[c]
+		for i := lo; i < hi; i++ {
+			if keep[i] {
+				result[k] = xs[i]
+				k++
+			}
+		}
+	})
+	return result
+}
@@ -0,0 +1,62 @@
+package concurrency_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+func TestParallelUnique(t *testing.T) {
+	parallel := []concurrency.ParallelOption{
+		concurrency.WithParallelism(4),
+		concurrency.WithChunkSize(100),
+		concurrency.WithSequentialThreshold(10),
+	}
+	tests := []struct {
+		name string
+		xs   []int
+		opts []concurrency.ParallelOption
+	}{
+		{"nil", nil, nil},
+		{"below threshold", []int{3, 1, 3, 2, 1}, nil},
+		{"few distinct", randomInts(10_000, 20), parallel},
+		{"mostly distinct", randomInts(10_000, 1_000_000), parallel},
+		{"all equal", make([]int, 1_000), parallel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := concurrency.ParallelUnique(tt.xs, tt.opts...)
+			if want := functional.Unique(tt.xs); !slices.Equal(got, want) || got == nil {
+				t.Errorf("ParallelUnique() differs from Unique(): got %d elements, want %d", len(got), len(want))
+			}
+		})
+	}
+}
+
+func ExampleParallelUnique() {
+	tags := []string{"go", "db", "go", "api", "db", "ops"}
+	fmt.Println(concurrency.ParallelUnique(tags))
+	// Output:
+	// [go db api ops]
+}
+
+// BenchmarkUnique compares functional.Unique with ParallelUnique across input
+// sizes, for inputs where about one element in ten is distinct.
+func BenchmarkUnique(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		xs := randomInts(n, n/10+1)
+		b.Run(fmt.Sprintf("Unique/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				functional.Unique(xs)
+			}
+		})
+		b.Run(fmt.Sprintf("ParallelUnique/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				concurrency.ParallelUnique(xs)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// The pipeline stages below connect channels with goroutines. Every stage
+// owns and closes its output channels, and every goroutine it starts exits
+// once its input is closed and drained or once ctx is done, whichever comes
+// first. Cancelling ctx is therefore enough to tear down a whole pipeline;
+// after cancellation, values still in flight are dropped. Callers must close
+// the input channels they own, or cancel ctx, to release the stages.
+
+// PipelineOption configures a pipeline stage.
+type PipelineOption func(*pipelineConfig)
+
+type pipelineConfig struct {
+	clock clock.Clock
+}
+
+// WithPipelineClock replaces the clock of a time-aware stage, so tests can
+// use a clock.FakeClock. The default is clock.Real().
+func WithPipelineClock(c clock.Clock) PipelineOption {
+	return func(cfg *pipelineConfig) { cfg.clock = c }
+}
+
+// send delivers v on out unless ctx is done first. It returns false if ctx
+// ended the wait.
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// receive reads the next value from in unless ctx is done first. It returns
+// false when in is closed or ctx is done.
+func receive[T any](ctx context.Context, in <-chan T) (T, bool) {
+	select {
+	case v, ok := <-in:
+		return v, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// OrDone forwards values from in until in is closed or ctx is done. It lets a
+// plain range loop over a channel honour cancellation:
+//
+//	for v := range OrDone(ctx, in) { ... }
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops forwarding when done.
+//	in: The source channel.
+//
+// Returns:
+//
+//	<-chan T: An unbuffered channel closed when forwarding stops.
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := receive(ctx, in)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// MapChan applies fn to every value received from in and sends the results,
+// in order, on the returned channel. It is the streaming counterpart of
+// functional.Map.
+//
+// Type Parameters:
+//
+//	T: The input element type.
+//	R: The output element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done.
+//	in: The source channel.
+//	fn: The transformation, called on a single goroutine.
+//
+// Returns:
+//
+//	<-chan R: An unbuffered channel closed when in is drained or ctx is done.
+func MapChan[T, R any](ctx context.Context, in <-chan T, fn func(T) R) <-chan R {
+	out := make(chan R)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := receive(ctx, in)
+			if !ok || !send(ctx, out, fn(v)) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FilterChan forwards the values received from in that satisfy pred, in
+// order. It is the streaming counterpart of functional.Filter.
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done.
+//	in: The source channel.
+//	pred: The predicate, called on a single goroutine.
+//
+// Returns:
+//
+//	<-chan T: An unbuffered channel closed when in is drained or ctx is done.
+func FilterChan[T any](ctx context.Context, in <-chan T, pred func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			if pred(v) && !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// BatchChan groups the values received from in into slices. A batch is sent
+// when it reaches size values, or when maxWait has passed since its first
+// value arrived, whichever comes first; a final partial batch is sent when in
+// is closed. It is the streaming counterpart of functional.Chunk.
+// Panics if size is not positive.
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done. A partial batch is dropped.
+//	in: The source channel.
+//	size: The maximum batch size. Must be positive.
+//	maxWait: The longest a value waits in a partial batch. Zero or negative
+//	         disables the time limit.
+//	opts: An optional WithPipelineClock.
+//
+// Returns:
+//
+//	<-chan []T: An unbuffered channel of non-empty batches, closed when in is
+//	            drained or ctx is done. Each batch is a new slice.
+func BatchChan[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration, opts ...PipelineOption) <-chan []T {
+	if size <= 0 {
+		panic("concurrency.BatchChan: size must be positive")
+	}
+	var cfg pipelineConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	clk := clock.OrReal(cfg.clock)
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var (
+			batch   []T
+			timer   clock.Timer
+			expired <-chan time.Time // nil while no partial batch is waiting
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+			}
+			expired = nil
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if len(batch) > 0 {
+						flush()
+					}
+					return
+				}
+				if batch == nil {
+					batch = make([]T, 0, size)
+					if maxWait > 0 {
+						if timer == nil {
+							timer = clk.NewTimer(maxWait)
+						} else {
+							timer.Reset(maxWait)
+						}
+						expired = timer.C()
+					}
+				}
+				batch = append(batch, v)
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-expired:
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanOut distributes the values received from in across n output channels,
+// each served by its own goroutine, so n consumers can share the work. Each
+// value goes to exactly one output: whichever consumer is ready first.
+// Panics if n is not positive.
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done.
+//	in: The source channel.
+//	n: The number of outputs. Must be positive.
+//
+// Returns:
+//
+//	[]<-chan T: n unbuffered channels, all closed when in is drained or ctx
+//	            is done.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		panic("concurrency.FanOut: n must be positive")
+	}
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for {
+				v, ok := receive(ctx, in)
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+	return outs
+}
+
+// Merge fans in: it forwards the values from all of the given channels onto a
+// single channel. Values from one input keep their order; values from
+// different inputs are interleaved in arrival order.
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done.
+//	ins: The source channels. With none, the output is closed immediately.
+//
+// Returns:
+//
+//	<-chan T: An unbuffered channel closed once every input is drained, or
+//	          when ctx is done.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func() {
+			defer wg.Done()
+			for {
+				v, ok := receive(ctx, in)
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Tee duplicates a channel: every value received from in is sent on both
+// outputs before the next value is read. The slower consumer therefore sets
+// the pace; put a Buffer after an output to decouple them.
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done.
+//	in: The source channel.
+//
+// Returns:
+//
+//	<-chan T, <-chan T: Two unbuffered channels, both closed when in is
+//	                    drained or ctx is done.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1, out2 := make(chan T), make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for {
+			v, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			// Send to whichever output is ready first, then to the other.
+			a, b := out1, out2
+			for range 2 {
+				select {
+				case a <- v:
+					a = nil
+				case b <- v:
+					b = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// Bridge flattens a channel of channels into a single channel, draining each
+// inner channel completely, in the order they arrive, before moving on to the
+// next. It is the streaming counterpart of functional.Flatten.
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done.
+//	chans: A channel of source channels.
+//
+// Returns:
+//
+//	<-chan T: An unbuffered channel closed when chans and the last inner
+//	          channel are drained, or when ctx is done.
+func Bridge[T any](ctx context.Context, chans <-chan (<-chan T)) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			in, ok := receive(ctx, chans)
+			if !ok {
+				return
+			}
+			if in == nil {
+				continue
+			}
+			for {
+				v, ok := receive(ctx, in)
+				if !ok {
+					break
+				}
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Buffer forwards the values from in through a channel with capacity size,
+// letting a fast producer run up to size values ahead of a slow consumer.
+// Panics if size is negative.
+//
+// Type Parameters:
+//
+//	T: The element type.
+//
+// Parameters:
+//
+//	ctx: Stops the stage when done.
+//	in: The source channel.
+//	size: The buffer capacity. Must not be negative.
+//
+// Returns:
+//
+//	<-chan T: A buffered channel closed when in is drained or ctx is done.
+func Buffer[T any](ctx context.Context, in <-chan T, size int) <-chan T {
+	if size < 0 {
+		panic("concurrency.Buffer: size must not be negative")
+	}
+	out := make(chan T, size)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := receive(ctx, in)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
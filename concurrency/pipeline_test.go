@@ -0,0 +1,321 @@
+package concurrency_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+// generate sends values on an unbuffered channel and closes it, stopping
+// early if ctx is cancelled.
+func generate[T any](ctx context.Context, values ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// collect drains a channel into a slice.
+func collect[T any](in <-chan T) []T {
+	result := []T{}
+	for v := range in {
+		result = append(result, v)
+	}
+	return result
+}
+
+// endless sends increasing integers until ctx is cancelled.
+func endless(ctx context.Context) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 0; ; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func TestMapFilterChan(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	in := generate(ctx, 1, 2, 3, 4, 5, 6)
+	evens := concurrency.FilterChan(ctx, in, func(n int) bool { return n%2 == 0 })
+	labels := concurrency.MapChan(ctx, evens, strconv.Itoa)
+
+	if got, want := collect(labels), []string{"2", "4", "6"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pipeline = %v, want %v", got, want)
+	}
+}
+
+func TestPipeline_CancellationReleasesEveryStage(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	src := endless(ctx)
+	doubled := concurrency.MapChan(ctx, src, func(n int) int { return n * 2 })
+	filtered := concurrency.FilterChan(ctx, doubled, func(n int) bool { return n%3 == 0 })
+	buffered := concurrency.Buffer(ctx, filtered, 4)
+	batches := concurrency.BatchChan(ctx, buffered, 3, time.Hour)
+	a, b := concurrency.Tee(ctx, concurrency.OrDone(ctx, batches))
+	workers := concurrency.FanOut(ctx, a, 3)
+	merged := concurrency.Merge(ctx, append(workers, b)...)
+
+	for range 10 {
+		<-merged
+	}
+	// Abandon the pipeline mid-stream without draining it.
+	cancel()
+}
+
+func TestBatchChan(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+
+	t.Run("BySize", func(t *testing.T) {
+		got := collect(concurrency.BatchChan(ctx, generate(ctx, 1, 2, 3, 4, 5, 6, 7), 3, 0))
+		want := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("BatchChan() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("ByFakeClock", func(t *testing.T) {
+		clock := newFakeClock()
+		in := make(chan int)
+		out := concurrency.BatchChan(ctx, in, 100, time.Minute, concurrency.WithPipelineClock(clock))
+		in <- 1
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		if got := <-out; !reflect.DeepEqual(got, []int{1}) {
+			t.Errorf("batch = %v, want [1]", got)
+		}
+		close(in)
+		if _, ok := <-out; ok {
+			t.Error("output not closed after input closed")
+		}
+	})
+
+	t.Run("ByTime", func(t *testing.T) {
+		in := make(chan int)
+		out := concurrency.BatchChan(ctx, in, 100, 20*time.Millisecond)
+		in <- 1
+		in <- 2
+		start := time.Now()
+		if got := <-out; !reflect.DeepEqual(got, []int{1, 2}) {
+			t.Errorf("first batch = %v, want [1 2]", got)
+		}
+		if waited := time.Since(start); waited > time.Second {
+			t.Errorf("partial batch took %v to flush", waited)
+		}
+		in <- 3
+		close(in)
+		if got := collect(out); !reflect.DeepEqual(got, [][]int{{3}}) {
+			t.Errorf("remaining batches = %v, want [[3]]", got)
+		}
+	})
+
+	t.Run("EmptyInput", func(t *testing.T) {
+		if got := collect(concurrency.BatchChan(ctx, generate[int](ctx), 2, time.Millisecond)); len(got) != 0 {
+			t.Errorf("BatchChan(empty) = %v, want no batches", got)
+		}
+	})
+}
+
+func TestFanOutMerge(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+
+	outs := concurrency.FanOut(ctx, generate(ctx, values...), 4)
+	if len(outs) != 4 {
+		t.Fatalf("FanOut() returned %d channels, want 4", len(outs))
+	}
+	squared := make([]<-chan int, len(outs))
+	for i, out := range outs {
+		squared[i] = concurrency.MapChan(ctx, out, func(n int) int { return n * n })
+	}
+	got := collect(concurrency.Merge(ctx, squared...))
+	sort.Ints(got)
+	for i, v := range got {
+		if v != i*i {
+			t.Fatalf("result %d = %d, want %d", i, v, i*i)
+		}
+	}
+	if len(got) != len(values) {
+		t.Errorf("got %d results, want %d", len(got), len(values))
+	}
+
+	if got := collect(concurrency.Merge[int](ctx)); len(got) != 0 {
+		t.Errorf("Merge() with no inputs = %v, want empty", got)
+	}
+}
+
+func TestMerge_KeepsPerInputOrder(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	got := collect(concurrency.Merge(ctx, generate(ctx, "a1", "a2", "a3"), generate(ctx, "b1", "b2")))
+
+	var a, b []string
+	for _, v := range got {
+		if v[0] == 'a' {
+			a = append(a, v)
+		} else {
+			b = append(b, v)
+		}
+	}
+	if fmt.Sprint(a) != "[a1 a2 a3]" || fmt.Sprint(b) != "[b1 b2]" {
+		t.Errorf("Merge() = %v, per-input order not kept", got)
+	}
+}
+
+func TestTee(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	a, b := concurrency.Tee(ctx, generate(ctx, 1, 2, 3))
+
+	var wg sync.WaitGroup
+	var gotA, gotB []int
+	wg.Add(2)
+	go func() { defer wg.Done(); gotA = collect(a) }()
+	go func() { defer wg.Done(); gotB = collect(b) }()
+	wg.Wait()
+
+	want := []int{1, 2, 3}
+	if !reflect.DeepEqual(gotA, want) || !reflect.DeepEqual(gotB, want) {
+		t.Errorf("Tee() = %v and %v, want %v on both", gotA, gotB, want)
+	}
+}
+
+func TestBridge(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx := context.Background()
+	chans := make(chan (<-chan int))
+	go func() {
+		defer close(chans)
+		chans <- generate(ctx, 1, 2)
+		chans <- nil
+		chans <- generate[int](ctx)
+		chans <- generate(ctx, 3)
+	}()
+
+	if got := collect(concurrency.Bridge(ctx, chans)); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Bridge() = %v, want [1 2 3]", got)
+	}
+}
+
+func TestBridge_Cancellation(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	chans := make(chan (<-chan int))
+	go func() {
+		defer close(chans)
+		select {
+		case chans <- endless(ctx):
+		case <-ctx.Done():
+		}
+	}()
+	out := concurrency.Bridge(ctx, chans)
+	<-out
+	<-out
+	cancel()
+}
+
+func TestOrDoneAndBuffer(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The source is never closed; only cancellation releases OrDone.
+	never := make(chan int)
+	done := concurrency.OrDone(ctx, never)
+	cancel()
+	if _, ok := <-done; ok {
+		t.Error("OrDone() output should close after cancellation")
+	}
+
+	in := make(chan int)
+	buffered := concurrency.Buffer(context.Background(), in, 3)
+	for i := range 3 {
+		in <- i // accepted without a reader because of the buffer
+	}
+	close(in)
+	if got := collect(buffered); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("Buffer() = %v, want [0 1 2]", got)
+	}
+}
+
+func TestPipeline_InvalidArgumentsPanic(t *testing.T) {
+	ctx := context.Background()
+	testCases := map[string]func(){
+		"BatchChanSize": func() { concurrency.BatchChan(ctx, make(chan int), 0, 0) },
+		"FanOutN":       func() { concurrency.FanOut(ctx, make(chan int), 0) },
+		"BufferSize":    func() { concurrency.Buffer(ctx, make(chan int), -1) },
+	}
+	for name, fn := range testCases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func ExampleBatchChan() {
+	ctx := context.Background()
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for i := range 7 {
+			lines <- fmt.Sprintf("row-%d", i)
+		}
+	}()
+
+	for batch := range concurrency.BatchChan(ctx, lines, 3, time.Second) {
+		fmt.Println(batch)
+	}
+	// Output:
+	// [row-0 row-1 row-2]
+	// [row-3 row-4 row-5]
+	// [row-6]
+}
+
+func BenchmarkMapChan(b *testing.B) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := concurrency.MapChan(ctx, in, func(n int) int { return n + 1 })
+	go func() {
+		defer close(in)
+		for i := 0; i < b.N; i++ {
+			in <- i
+		}
+	}()
+	b.ResetTimer()
+	for range out {
+	}
+}
@@ -0,0 +1,426 @@
+// Package concurrency provides generic building blocks for concurrent Go
+// programs: worker pools, channel pipelines and related utilities that
+// complement the slice helpers in the functional package.
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+var (
+	// ErrPoolClosed is returned when submitting to a pool that is draining or
+	// stopped, and is the error of queued jobs discarded by Stop.
+	ErrPoolClosed = errors.New("concurrency: pool closed")
+	// ErrQueueFull is returned by TrySubmit when the submit queue has no room.
+	ErrQueueFull = errors.New("concurrency: pool queue full")
+)
+
+// PoolOption configures a Pool.
+type PoolOption func(*poolConfig)
+
+type poolConfig struct {
+	minWorkers   int
+	maxWorkers   int
+	idleTimeout  time.Duration
+	queueSize    int // -1 means "same as maxWorkers"
+	resultBuffer int // -1 means no result channel
+	clock        clock.Clock
+}
+
+// WithWorkers runs a fixed number of workers. It panics if n is not positive.
+// The default is runtime.GOMAXPROCS(0).
+func WithWorkers(n int) PoolOption {
+	if n <= 0 {
+		panic("concurrency.WithWorkers: n must be positive")
+	}
+	return func(c *poolConfig) {
+		c.minWorkers, c.maxWorkers = n, n
+	}
+}
+
+// WithElasticWorkers keeps min workers running and starts more, up to max,
+// while jobs are waiting and no worker is idle. A worker above min exits
+// after idleTimeout without work. It panics unless 1 <= min <= max and
+// idleTimeout is positive.
+func WithElasticWorkers(min, max int, idleTimeout time.Duration) PoolOption {
+	if min < 1 || max < min || idleTimeout <= 0 {
+		panic("concurrency.WithElasticWorkers: need 1 <= min <= max and a positive idleTimeout")
+	}
+	return func(c *poolConfig) {
+		c.minWorkers, c.maxWorkers, c.idleTimeout = min, max, idleTimeout
+	}
+}
+
+// WithQueueSize sets how many submitted jobs may wait for a worker before
+// Submit blocks and TrySubmit fails. Zero makes every submit hand its job
+// directly to a worker. It panics if n is negative. The default is the
+// maximum worker count.
+func WithQueueSize(n int) PoolOption {
+	if n < 0 {
+		panic("concurrency.WithQueueSize: n must not be negative")
+	}
+	return func(c *poolConfig) { c.queueSize = n }
+}
+
+// WithResultChannel publishes every finished job on the channel returned by
+// Results, buffered to hold buffer results. Workers block until each result
+// is received, so the channel must be read until it is closed, which happens
+// once the pool has drained or stopped. It panics if buffer is negative.
+func WithResultChannel(buffer int) PoolOption {
+	if buffer < 0 {
+		panic("concurrency.WithResultChannel: buffer must not be negative")
+	}
+	return func(c *poolConfig) { c.resultBuffer = buffer }
+}
+
+// WithPoolClock replaces the clock that times idle elastic workers, so tests
+// can use a clock.FakeClock. The default is clock.Real().
+func WithPoolClock(c clock.Clock) PoolOption {
+	return func(cfg *poolConfig) { cfg.clock = c }
+}
+
+// PoolStats is a point-in-time snapshot of a pool's counters. The fields are
+// read independently, so under load they may not add up exactly.
+type PoolStats struct {
+	Workers   int    // running worker goroutines
+	Queued    int    // jobs waiting for a worker
+	Active    int    // jobs currently executing
+	Completed uint64 // jobs that returned a nil error
+	Failed    uint64 // jobs that returned an error, panicked or were discarded
+}
+
+// PoolResult is a finished job as published on the Results channel.
+type PoolResult[In, Out any] struct {
+	Input In
+	Value Out
+	Err   error
+}
+
+// Job is the handle for a submitted job. Its result becomes available once
+// Done is closed.
+type Job[Out any] struct {
+	done  chan struct{}
+	value Out
+	err   error
+}
+
+// Done returns a channel that is closed when the job has finished.
+func (j *Job[Out]) Done() <-chan struct{} {
+	return j.done
+}
+
+// Wait blocks until the job finishes and returns its result, or returns
+// ctx.Err() if ctx is done first. Abandoning the wait does not cancel the job.
+func (j *Job[Out]) Wait(ctx context.Context) (Out, error) {
+	select {
+	case <-j.done:
+		return j.value, j.err
+	case <-ctx.Done():
+		var zero Out
+		return zero, ctx.Err()
+	}
+}
+
+type poolJob[In, Out any] struct {
+	ctx    context.Context
+	input  In
+	handle *Job[Out]
+}
+
+// Pool runs a function over submitted inputs on a bounded set of worker
+// goroutines. Create one with NewPool and shut it down with Drain or Stop.
+// All methods are safe for concurrent use.
+type Pool[In, Out any] struct {
+	fn      func(context.Context, In) (Out, error)
+	cfg     poolConfig
+	queue   chan *poolJob[In, Out]
+	results chan PoolResult[In, Out]
+
+	ctx         context.Context // cancelled by Stop
+	cancel      context.CancelFunc
+	closing     chan struct{} // closed when shutdown starts, to release blocked submitters
+	closingOnce sync.Once
+	done        chan struct{} // closed when every worker has exited
+
+	mu     sync.RWMutex // guards closed and the closing of queue
+	closed bool
+
+	workerMu sync.Mutex
+	workers  int
+	idle     int
+	wg       sync.WaitGroup
+
+	queued    atomic.Int64
+	active    atomic.Int64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// NewPool starts a worker pool that calls fn for each submitted input.
+//
+// Type Parameters:
+//
+//	In: The job input type.
+//	Out: The job result type.
+//
+// Parameters:
+//
+//	fn: The job function. Its context is derived from the submitter's
+//	    context and is also cancelled by Stop. A panic in fn is recovered
+//	    and reported as a *PanicError.
+//	opts: Worker count, queue size, result channel and clock settings.
+//
+// Returns:
+//
+//	*Pool[In, Out]: A running pool.
+func NewPool[In, Out any](fn func(ctx context.Context, in In) (Out, error), opts ...PoolOption) *Pool[In, Out] {
+	n := runtime.GOMAXPROCS(0)
+	cfg := poolConfig{minWorkers: n, maxWorkers: n, queueSize: -1, resultBuffer: -1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.queueSize < 0 {
+		cfg.queueSize = cfg.maxWorkers
+	}
+	cfg.clock = clock.OrReal(cfg.clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool[In, Out]{
+		fn:      fn,
+		cfg:     cfg,
+		queue:   make(chan *poolJob[In, Out], cfg.queueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if cfg.resultBuffer >= 0 {
+		p.results = make(chan PoolResult[In, Out], cfg.resultBuffer)
+	}
+
+	p.workerMu.Lock()
+	for range cfg.minWorkers {
+		p.startWorkerLocked()
+	}
+	p.workerMu.Unlock()
+	return p
+}
+
+// Submit queues in for processing, blocking while the queue is full. It
+// returns ctx.Err() if ctx is done before the job is queued, and
+// ErrPoolClosed if the pool is shutting down.
+func (p *Pool[In, Out]) Submit(ctx context.Context, in In) (*Job[Out], error) {
+	return p.submit(ctx, in, true)
+}
+
+// TrySubmit queues in for processing without blocking. It returns
+// ErrQueueFull if the queue has no room, and ErrPoolClosed if the pool is
+// shutting down. ctx becomes the parent of the job's context.
+func (p *Pool[In, Out]) TrySubmit(ctx context.Context, in In) (*Job[Out], error) {
+	return p.submit(ctx, in, false)
+}
+
+func (p *Pool[In, Out]) submit(ctx context.Context, in In, block bool) (*Job[Out], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	j := &poolJob[In, Out]{ctx: ctx, input: in, handle: &Job[Out]{done: make(chan struct{})}}
+	p.queued.Add(1)
+	p.maybeGrow()
+
+	if !block {
+		select {
+		case p.queue <- j:
+			return j.handle, nil
+		default:
+			p.queued.Add(-1)
+			return nil, ErrQueueFull
+		}
+	}
+	select {
+	case p.queue <- j:
+		return j.handle, nil
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		return nil, ctx.Err()
+	case <-p.closing:
+		p.queued.Add(-1)
+		return nil, ErrPoolClosed
+	}
+}
+
+// Results returns the channel on which finished jobs are published, or nil
+// if the pool was created without WithResultChannel.
+func (p *Pool[In, Out]) Results() <-chan PoolResult[In, Out] {
+	return p.results
+}
+
+// Stats returns a snapshot of the pool's counters.
+func (p *Pool[In, Out]) Stats() PoolStats {
+	p.workerMu.Lock()
+	workers := p.workers
+	p.workerMu.Unlock()
+	return PoolStats{
+		Workers:   workers,
+		Queued:    int(p.queued.Load()),
+		Active:    int(p.active.Load()),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+	}
+}
+
+// Drain stops accepting new jobs and waits for every queued and running job
+// to finish. If ctx is done first, Drain returns ctx.Err() while the
+// remaining jobs keep running; call Stop to abandon them.
+func (p *Pool[In, Out]) Drain(ctx context.Context) error {
+	p.shutdown()
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop stops accepting new jobs, cancels the context of running jobs,
+// discards queued jobs with ErrPoolClosed, and waits for the workers to
+// exit. It is safe to call after Drain, for example when Drain timed out.
+func (p *Pool[In, Out]) Stop() {
+	p.cancel()
+	p.shutdown()
+	<-p.done
+}
+
+func (p *Pool[In, Out]) shutdown() {
+	// Release blocked submitters first: they hold the read lock.
+	p.closingOnce.Do(func() { close(p.closing) })
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	close(p.queue)
+	go func() {
+		p.wg.Wait()
+		if p.results != nil {
+			close(p.results)
+		}
+		p.cancel()
+		close(p.done)
+	}()
+}
+
+// maybeGrow starts an extra elastic worker when more jobs are waiting than
+// workers are idle.
+func (p *Pool[In, Out]) maybeGrow() {
+	p.workerMu.Lock()
+	defer p.workerMu.Unlock()
+	if p.workers < p.cfg.maxWorkers && int(p.queued.Load()) > p.idle {
+		p.startWorkerLocked()
+	}
+}
+
+func (p *Pool[In, Out]) startWorkerLocked() {
+	p.workers++
+	p.wg.Add(1)
+	go p.worker()
+}
+
+func (p *Pool[In, Out]) worker() {
+	defer p.wg.Done()
+	for {
+		j, ok := p.next()
+		if !ok {
+			return
+		}
+		p.run(j)
+	}
+}
+
+// next waits for a job. It returns false when the queue is closed and empty,
+// or when an elastic worker above the minimum has been idle too long.
+func (p *Pool[In, Out]) next() (*poolJob[In, Out], bool) {
+	p.workerMu.Lock()
+	p.idle++
+	p.workerMu.Unlock()
+
+	var idle <-chan time.Time
+	if p.cfg.maxWorkers > p.cfg.minWorkers {
+		timer := p.cfg.clock.NewTimer(p.cfg.idleTimeout)
+		defer timer.Stop()
+		idle = timer.C()
+	}
+
+	for {
+		select {
+		case j, ok := <-p.queue:
+			p.workerMu.Lock()
+			p.idle--
+			if !ok {
+				p.workers--
+			}
+			p.workerMu.Unlock()
+			if ok {
+				p.queued.Add(-1)
+			}
+			return j, ok
+		case <-idle:
+			p.workerMu.Lock()
+			if p.workers > p.cfg.minWorkers {
+				p.idle--
+				p.workers--
+				p.workerMu.Unlock()
+				return nil, false
+			}
+			p.workerMu.Unlock()
+			idle = nil // at the minimum: wait for work without a deadline
+		}
+	}
+}
+
+func (p *Pool[In, Out]) run(j *poolJob[In, Out]) {
+	var (
+		value Out
+		err   error
+	)
+	switch {
+	case p.ctx.Err() != nil:
+		err = ErrPoolClosed
+	case j.ctx.Err() != nil:
+		err = j.ctx.Err()
+	default:
+		p.active.Add(1)
+		ctx, cancel := context.WithCancel(j.ctx)
+		stop := context.AfterFunc(p.ctx, cancel)
+		value, err = callSafely(func() (Out, error) { return p.fn(ctx, j.input) })
+		stop()
+		cancel()
+		p.active.Add(-1)
+	}
+
+	if err != nil {
+		p.failed.Add(1)
+	} else {
+		p.completed.Add(1)
+	}
+	j.handle.value, j.handle.err = value, err
+	close(j.handle.done)
+	if p.results != nil {
+		p.results <- PoolResult[In, Out]{Input: j.input, Value: value, Err: err}
+	}
+}
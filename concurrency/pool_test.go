@@ -0,0 +1,352 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+func double(_ context.Context, n int) (int, error) {
+	return n * 2, nil
+}
+
+func TestPool_SubmitAndWait(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p := concurrency.NewPool(double, concurrency.WithWorkers(4))
+	defer p.Stop()
+
+	ctx := context.Background()
+	jobs := make([]*concurrency.Job[int], 0, 100)
+	for i := range 100 {
+		j, err := p.Submit(ctx, i)
+		if err != nil {
+			t.Fatalf("Submit(%d) error = %v", i, err)
+		}
+		jobs = append(jobs, j)
+	}
+	for i, j := range jobs {
+		got, err := j.Wait(ctx)
+		if err != nil || got != i*2 {
+			t.Errorf("job %d = (%d, %v), want (%d, nil)", i, got, err, i*2)
+		}
+	}
+	if err := p.Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if s := p.Stats(); s.Completed != 100 || s.Failed != 0 || s.Queued != 0 || s.Active != 0 || s.Workers != 0 {
+		t.Errorf("Stats() after Drain = %+v", s)
+	}
+}
+
+func TestPool_ErrorsAndPanics(t *testing.T) {
+	checkGoroutineLeaks(t)
+	errOdd := errors.New("odd")
+	p := concurrency.NewPool(func(_ context.Context, n int) (string, error) {
+		switch {
+		case n == 3:
+			panic(fmt.Sprintf("bad input %d", n))
+		case n%2 == 1:
+			return "", errOdd
+		}
+		return fmt.Sprint(n), nil
+	}, concurrency.WithWorkers(2))
+
+	ctx := context.Background()
+	var jobs []*concurrency.Job[string]
+	for i := range 4 {
+		j, _ := p.Submit(ctx, i)
+		jobs = append(jobs, j)
+	}
+	if err := p.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jobs[1].Wait(ctx); !errors.Is(err, errOdd) {
+		t.Errorf("job 1 error = %v, want errOdd", err)
+	}
+	_, err := jobs[3].Wait(ctx)
+	var pe *concurrency.PanicError
+	if !errors.As(err, &pe) || pe.Value != "bad input 3" || !strings.Contains(string(pe.Stack), "pool_test.go") {
+		t.Errorf("job 3 error = %v, want a PanicError with a stack", err)
+	}
+	if s := p.Stats(); s.Completed != 2 || s.Failed != 2 {
+		t.Errorf("Stats() = %+v, want 2 completed and 2 failed", s)
+	}
+}
+
+func TestPool_PanicWithErrorUnwraps(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p := concurrency.NewPool(func(context.Context, int) (int, error) {
+		panic(context.DeadlineExceeded)
+	}, concurrency.WithWorkers(1))
+	defer p.Stop()
+
+	j, _ := p.Submit(context.Background(), 1)
+	if _, err := j.Wait(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want it to unwrap to context.DeadlineExceeded", err)
+	}
+}
+
+// blockingPool returns a pool whose jobs block until release is closed.
+func blockingPool(t *testing.T, opts ...concurrency.PoolOption) (*concurrency.Pool[int, int], chan struct{}, *atomic.Int64) {
+	t.Helper()
+	release := make(chan struct{})
+	var started atomic.Int64
+	p := concurrency.NewPool(func(ctx context.Context, n int) (int, error) {
+		started.Add(1)
+		select {
+		case <-release:
+			return n, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}, opts...)
+	return p, release, &started
+}
+
+func TestPool_TrySubmitAndBackpressure(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p, release, started := blockingPool(t, concurrency.WithWorkers(1), concurrency.WithQueueSize(1))
+	ctx := context.Background()
+
+	if _, err := p.TrySubmit(ctx, 1); err != nil {
+		t.Fatalf("first TrySubmit() error = %v", err)
+	}
+	eventually(t, time.Second, func() bool { return started.Load() == 1 }, "first job started")
+	if _, err := p.TrySubmit(ctx, 2); err != nil {
+		t.Fatalf("second TrySubmit() error = %v", err)
+	}
+	if _, err := p.TrySubmit(ctx, 3); !errors.Is(err, concurrency.ErrQueueFull) {
+		t.Fatalf("third TrySubmit() error = %v, want ErrQueueFull", err)
+	}
+	if s := p.Stats(); s.Queued != 1 || s.Active != 1 {
+		t.Errorf("Stats() = %+v, want 1 queued and 1 active", s)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.Submit(timeout, 4); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("blocked Submit() error = %v, want DeadlineExceeded", err)
+	}
+
+	close(release)
+	if err := p.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s := p.Stats(); s.Completed != 2 {
+		t.Errorf("Stats() = %+v, want 2 completed", s)
+	}
+}
+
+func TestPool_StopCancelsAndDiscards(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p, _, started := blockingPool(t, concurrency.WithWorkers(1), concurrency.WithQueueSize(2))
+	ctx := context.Background()
+
+	running, _ := p.Submit(ctx, 1)
+	eventually(t, time.Second, func() bool { return started.Load() == 1 }, "first job started")
+	queued, _ := p.Submit(ctx, 2)
+
+	// A submitter blocked on the full queue is released by Stop.
+	_, _ = p.Submit(ctx, 3)
+	blocked := make(chan error, 1)
+	go func() {
+		_, err := p.Submit(ctx, 4)
+		blocked <- err
+	}()
+
+	p.Stop()
+	if _, err := running.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("running job error = %v, want context.Canceled", err)
+	}
+	if _, err := queued.Wait(ctx); !errors.Is(err, concurrency.ErrPoolClosed) {
+		t.Errorf("queued job error = %v, want ErrPoolClosed", err)
+	}
+	if err := <-blocked; !errors.Is(err, concurrency.ErrPoolClosed) {
+		t.Errorf("blocked Submit() error = %v, want ErrPoolClosed", err)
+	}
+	if _, err := p.Submit(ctx, 5); !errors.Is(err, concurrency.ErrPoolClosed) {
+		t.Errorf("Submit() after Stop error = %v, want ErrPoolClosed", err)
+	}
+	if started.Load() != 1 {
+		t.Errorf("%d jobs started, want only the first", started.Load())
+	}
+}
+
+func TestPool_DrainTimeoutThenStop(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p, _, started := blockingPool(t, concurrency.WithWorkers(1))
+	_, _ = p.Submit(context.Background(), 1)
+	eventually(t, time.Second, func() bool { return started.Load() == 1 }, "job started")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain() error = %v, want DeadlineExceeded", err)
+	}
+	p.Stop()
+}
+
+func TestPool_ResultChannel(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p := concurrency.NewPool(double, concurrency.WithWorkers(3), concurrency.WithResultChannel(0))
+	go func() {
+		for i := range 10 {
+			_, _ = p.Submit(context.Background(), i)
+		}
+		_ = p.Drain(context.Background())
+	}()
+
+	var inputs []int
+	for r := range p.Results() {
+		if r.Err != nil || r.Value != r.Input*2 {
+			t.Errorf("result %+v is wrong", r)
+		}
+		inputs = append(inputs, r.Input)
+	}
+	sort.Ints(inputs)
+	if fmt.Sprint(inputs) != "[0 1 2 3 4 5 6 7 8 9]" {
+		t.Errorf("results for inputs %v, want 0..9", inputs)
+	}
+	plain := concurrency.NewPool(double, concurrency.WithWorkers(1))
+	defer plain.Stop()
+	if plain.Results() != nil {
+		t.Error("Results() without WithResultChannel should be nil")
+	}
+}
+
+func TestPool_ElasticWorkers(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p, release, started := blockingPool(t,
+		concurrency.WithElasticWorkers(1, 4, 20*time.Millisecond),
+		concurrency.WithQueueSize(8))
+	defer p.Stop()
+	if w := p.Stats().Workers; w != 1 {
+		t.Fatalf("initial Workers = %d, want 1", w)
+	}
+
+	for i := range 6 {
+		if _, err := p.Submit(context.Background(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	eventually(t, time.Second, func() bool { return started.Load() == 4 }, "pool grew to 4 workers")
+	if w := p.Stats().Workers; w != 4 {
+		t.Errorf("Workers under load = %d, want 4", w)
+	}
+
+	close(release)
+	eventually(t, 2*time.Second, func() bool {
+		s := p.Stats()
+		return s.Completed == 6 && s.Workers == 1
+	}, "pool shrank back to 1 worker")
+}
+
+func TestPool_SubmitterContextCancelsJob(t *testing.T) {
+	checkGoroutineLeaks(t)
+	p, _, started := blockingPool(t, concurrency.WithWorkers(1))
+	defer p.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j, _ := p.Submit(ctx, 1)
+	eventually(t, time.Second, func() bool { return started.Load() == 1 }, "job started")
+	cancel()
+	if _, err := j.Wait(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("job error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPool_InvalidOptionsPanic(t *testing.T) {
+	testCases := map[string]func(){
+		"WorkersZero":     func() { concurrency.WithWorkers(0) },
+		"ElasticMinZero":  func() { concurrency.WithElasticWorkers(0, 2, time.Second) },
+		"ElasticMaxBelow": func() { concurrency.WithElasticWorkers(3, 2, time.Second) },
+		"ElasticNoIdle":   func() { concurrency.WithElasticWorkers(1, 2, 0) },
+		"NegativeQueue":   func() { concurrency.WithQueueSize(-1) },
+		"NegativeResults": func() { concurrency.WithResultChannel(-1) },
+	}
+	for name, fn := range testCases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func ExampleNewPool() {
+	p := concurrency.NewPool(func(_ context.Context, word string) (int, error) {
+		return len(word), nil
+	}, concurrency.WithWorkers(2))
+
+	ctx := context.Background()
+	var jobs []*concurrency.Job[int]
+	for _, w := range []string{"alpha", "be", "gamma"} {
+		j, err := p.Submit(ctx, w)
+		if err != nil {
+			panic(err)
+		}
+		jobs = append(jobs, j)
+	}
+	for _, j := range jobs {
+		n, _ := j.Wait(ctx)
+		fmt.Println(n)
+	}
+	_ = p.Drain(ctx)
+	fmt.Printf("%+v\n", p.Stats())
+	// Output:
+	// 5
+	// 2
+	// 5
+	// {Workers:0 Queued:0 Active:0 Completed:3 Failed:0}
+}
+
+func BenchmarkPool_Submit(b *testing.B) {
+	p := concurrency.NewPool(double, concurrency.WithQueueSize(1024))
+	defer p.Stop()
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		j, _ := p.Submit(ctx, i)
+		if i%1024 == 0 {
+			_, _ = j.Wait(ctx)
+		}
+	}
+}
+
+func TestPool_ElasticWorkersFakeClock(t *testing.T) {
+	checkGoroutineLeaks(t)
+	clock := newFakeClock()
+	p, release, started := blockingPool(t,
+		concurrency.WithElasticWorkers(1, 4, time.Minute),
+		concurrency.WithQueueSize(8),
+		concurrency.WithPoolClock(clock))
+	defer p.Stop()
+
+	for i := range 6 {
+		if _, err := p.Submit(context.Background(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	eventually(t, time.Second, func() bool { return started.Load() == 4 }, "pool grew to 4 workers")
+	close(release)
+
+	// Every idle worker waits on an idle timer; only those above the
+	// minimum exit when it fires.
+	clock.BlockUntil(4)
+	if w := p.Stats().Workers; w != 4 {
+		t.Fatalf("Workers before the idle timeout = %d, want 4", w)
+	}
+	clock.Advance(time.Minute)
+	eventually(t, time.Second, func() bool { return p.Stats().Workers == 1 }, "pool shrank back to 1 worker")
+}
@@ -0,0 +1,468 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// ErrLimiterFull is returned by Wait when a LeakyBucket has no room left to
+// queue another request.
+var ErrLimiterFull = errors.New("concurrency: rate limiter full")
+
+// Rate is a number of events allowed per period, such as Rate{N: 100, Per:
+// time.Minute}.
+type Rate struct {
+	N   int
+	Per time.Duration
+}
+
+// PerSecond returns a Rate of n events per second.
+func PerSecond(n int) Rate {
+	return Rate{N: n, Per: time.Second}
+}
+
+func (r Rate) validate(fn string) {
+	if r.N <= 0 || r.Per <= 0 {
+		panic("concurrency." + fn + ": rate needs a positive N and Per")
+	}
+}
+
+// interval is the average time between events.
+func (r Rate) interval() time.Duration {
+	return r.Per / time.Duration(r.N)
+}
+
+// RateLimiter controls how frequently events may happen. The implementations
+// in this package are TokenBucket, LeakyBucket and SlidingWindow; all are
+// safe for concurrent use.
+type RateLimiter interface {
+	// Allow reports whether an event may happen now, consuming the
+	// allowance if so. It never blocks.
+	Allow() bool
+	// Wait blocks until an event may happen or ctx is done. If ctx ends the
+	// wait, the reserved allowance is returned to the limiter.
+	Wait(ctx context.Context) error
+	// Reserve claims the next available allowance without blocking and
+	// reports when it may be used. Callers must wait until
+	// Reservation.Delay has passed, or call Cancel to give it back.
+	Reserve() *Reservation
+	// SetRate changes the rate from now on.
+	SetRate(r Rate)
+	// Rate returns the current rate.
+	Rate() Rate
+}
+
+// LimiterOption configures a rate limiter.
+type LimiterOption func(*limiterConfig)
+
+type limiterConfig struct {
+	clock clock.Clock
+}
+
+// WithLimiterClock replaces the clock of a limiter, so tests can drive it
+// with a clock.FakeClock instead of real sleeps. The default is
+// clock.Real().
+func WithLimiterClock(c clock.Clock) LimiterOption {
+	return func(cfg *limiterConfig) { cfg.clock = c }
+}
+
+func newLimiterConfig(opts []LimiterOption) limiterConfig {
+	var cfg limiterConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.clock = clock.OrReal(cfg.clock)
+	return cfg
+}
+
+// algorithm is the bookkeeping that distinguishes the limiter types. Its
+// methods are called with limiter.mu held.
+type algorithm interface {
+	// reserve claims the next slot at or after now. If wait is false, it
+	// only claims a slot available at now. undo gives the slot back.
+	reserve(now time.Time, wait bool) (readyAt time.Time, undo func(), ok bool)
+	setRate(now time.Time, r Rate)
+	rate() Rate
+}
+
+// limiter implements RateLimiter on top of an algorithm.
+type limiter struct {
+	mu  sync.Mutex
+	alg algorithm
+	cfg limiterConfig
+}
+
+func (l *limiter) reserve(wait bool) *Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	readyAt, undo, ok := l.alg.reserve(l.cfg.clock.Now(), wait)
+	return &Reservation{ok: ok, readyAt: readyAt, undo: undo, l: l}
+}
+
+// Allow reports whether an event may happen now.
+func (l *limiter) Allow() bool {
+	return l.reserve(false).ok
+}
+
+// Reserve claims the next available allowance without blocking.
+func (l *limiter) Reserve() *Reservation {
+	return l.reserve(true)
+}
+
+// Wait blocks until an event may happen or ctx is done.
+func (l *limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r := l.reserve(true)
+	if !r.ok {
+		return ErrLimiterFull
+	}
+	d := r.Delay()
+	if d <= 0 {
+		return nil
+	}
+	timer := l.cfg.clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// SetRate changes the rate from now on.
+func (l *limiter) SetRate(r Rate) {
+	r.validate("SetRate")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.alg.setRate(l.cfg.clock.Now(), r)
+}
+
+// Rate returns the current rate.
+func (l *limiter) Rate() Rate {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.alg.rate()
+}
+
+// Reservation is an allowance claimed with Reserve.
+type Reservation struct {
+	ok      bool
+	readyAt time.Time
+	undo    func()
+	l       *limiter
+}
+
+// OK reports whether the allowance was granted. A LeakyBucket refuses
+// reservations when its queue is full.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// ReadyAt returns the time at which the allowance may be used. It is the zero
+// time if the reservation is not OK.
+func (r *Reservation) ReadyAt() time.Time {
+	return r.readyAt
+}
+
+// Delay returns how long to wait before using the allowance, which is zero
+// if it may be used now or if the reservation is not OK.
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return 0
+	}
+	return max(0, r.readyAt.Sub(r.l.cfg.clock.Now()))
+}
+
+// Cancel gives the allowance back so others may use it. It has no effect once
+// the allowance is usable, if the reservation is not OK, or if it was already
+// cancelled.
+func (r *Reservation) Cancel() {
+	if !r.ok {
+		return
+	}
+	r.l.mu.Lock()
+	defer r.l.mu.Unlock()
+	if r.undo == nil || !r.l.cfg.clock.Now().Before(r.readyAt) {
+		return
+	}
+	r.undo()
+	r.undo = nil
+}
+
+// TokenBucket is a RateLimiter that refills tokens at a steady rate up to a
+// burst size; each event takes one token. It allows short bursts while
+// bounding the long-run average rate. Waiting events borrow future tokens,
+// so they are released in arrival order.
+type TokenBucket struct {
+	limiter
+}
+
+type tokenBucket struct {
+	r      Rate
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a TokenBucket that starts full with burst tokens and
+// refills at rate r. It panics if r is invalid or burst is not positive.
+func NewTokenBucket(r Rate, burst int, opts ...LimiterOption) *TokenBucket {
+	r.validate("NewTokenBucket")
+	if burst <= 0 {
+		panic("concurrency.NewTokenBucket: burst must be positive")
+	}
+	cfg := newLimiterConfig(opts)
+	alg := &tokenBucket{r: r, burst: burst, tokens: float64(burst), last: cfg.clock.Now()}
+	return &TokenBucket{limiter{alg: alg, cfg: cfg}}
+}
+
+// Tokens returns the number of tokens currently available. It is negative
+// while waiting reservations have borrowed future tokens.
+func (b *TokenBucket) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tb := b.alg.(*tokenBucket)
+	tb.advance(b.cfg.clock.Now())
+	return tb.tokens
+}
+
+func (b *tokenBucket) perSecond() float64 {
+	return float64(b.r.N) / b.r.Per.Seconds()
+}
+
+func (b *tokenBucket) advance(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(float64(b.burst), b.tokens+now.Sub(b.last).Seconds()*b.perSecond())
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) reserve(now time.Time, wait bool) (time.Time, func(), bool) {
+	b.advance(now)
+	readyAt := now
+	if b.tokens < 1 {
+		if !wait {
+			return time.Time{}, nil, false
+		}
+		readyAt = now.Add(time.Duration(math.Ceil((1 - b.tokens) / b.perSecond() * float64(time.Second))))
+	}
+	b.tokens--
+	return readyAt, func() { b.tokens = math.Min(float64(b.burst), b.tokens+1) }, true
+}
+
+func (b *tokenBucket) setRate(now time.Time, r Rate) {
+	b.advance(now) // tokens earned so far accrue at the old rate
+	b.r = r
+}
+
+func (b *tokenBucket) rate() Rate { return b.r }
+
+// LeakyBucket is a RateLimiter that spaces events evenly, one every Per/N,
+// with no bursts. Up to capacity events may wait for their turn; beyond
+// that, Reserve is not OK and Wait returns ErrLimiterFull.
+type LeakyBucket struct {
+	limiter
+}
+
+type leakyBucket struct {
+	r        Rate
+	capacity int
+	next     time.Time // earliest time of the next free slot
+}
+
+// NewLeakyBucket returns a LeakyBucket that releases events at rate r and
+// queues at most capacity waiting events. It panics if r is invalid or
+// capacity is negative.
+func NewLeakyBucket(r Rate, capacity int, opts ...LimiterOption) *LeakyBucket {
+	r.validate("NewLeakyBucket")
+	if capacity < 0 {
+		panic("concurrency.NewLeakyBucket: capacity must not be negative")
+	}
+	return &LeakyBucket{limiter{alg: &leakyBucket{r: r, capacity: capacity}, cfg: newLimiterConfig(opts)}}
+}
+
+func (b *leakyBucket) reserve(now time.Time, wait bool) (time.Time, func(), bool) {
+	slot := now
+	if b.next.After(now) {
+		slot = b.next
+	}
+	if slot.After(now) {
+		// Events already queued ahead of this one, each holding a slot.
+		ahead := (slot.Sub(now) - 1) / b.r.interval()
+		if !wait || ahead >= time.Duration(b.capacity) {
+			return time.Time{}, nil, false
+		}
+	}
+	b.next = slot.Add(b.r.interval())
+	end := b.next
+	return slot, func() {
+		// Only the most recent slot can be handed back without
+		// disturbing the spacing of later ones.
+		if b.next.Equal(end) {
+			b.next = slot
+		}
+	}, true
+}
+
+func (b *leakyBucket) setRate(_ time.Time, r Rate) { b.r = r }
+
+func (b *leakyBucket) rate() Rate { return b.r }
+
+// SlidingWindow is a RateLimiter that allows at most N events in any period
+// of length Per, tracked with a log of event times. It is exact, unlike
+// bucket approximations, at the cost of storing up to N timestamps.
+type SlidingWindow struct {
+	limiter
+}
+
+type slidingWindow struct {
+	r   Rate
+	log []time.Time // sorted; includes future slots claimed by reservations
+}
+
+// NewSlidingWindow returns a SlidingWindow allowing r.N events per r.Per. It
+// panics if r is invalid.
+func NewSlidingWindow(r Rate, opts ...LimiterOption) *SlidingWindow {
+	r.validate("NewSlidingWindow")
+	return &SlidingWindow{limiter{alg: &slidingWindow{r: r}, cfg: newLimiterConfig(opts)}}
+}
+
+func (w *slidingWindow) reserve(now time.Time, wait bool) (time.Time, func(), bool) {
+	// Forget events that have left the window.
+	cutoff := now.Add(-w.r.Per)
+	drop := 0
+	for drop < len(w.log) && !w.log[drop].After(cutoff) {
+		drop++
+	}
+	w.log = slices.Delete(w.log, 0, drop)
+
+	slot := now
+	if len(w.log) >= w.r.N {
+		if t := w.log[len(w.log)-w.r.N].Add(w.r.Per); t.After(now) {
+			slot = t
+		}
+	}
+	if slot.After(now) && !wait {
+		return time.Time{}, nil, false
+	}
+	i, _ := slices.BinarySearchFunc(w.log, slot, func(a, b time.Time) int { return a.Compare(b) })
+	w.log = slices.Insert(w.log, i, slot)
+	return slot, func() {
+		if i, found := slices.BinarySearchFunc(w.log, slot, func(a, b time.Time) int { return a.Compare(b) }); found {
+			w.log = slices.Delete(w.log, i, i+1)
+		}
+	}, true
+}
+
+func (w *slidingWindow) setRate(_ time.Time, r Rate) { w.r = r }
+
+func (w *slidingWindow) rate() Rate { return w.r }
+
+// KeyedLimiter keeps a separate RateLimiter per key, such as per user or per
+// API host, creating each on first use and evicting it once it has been idle
+// for the configured timeout. It is safe for concurrent use.
+type KeyedLimiter[K comparable] struct {
+	mu          sync.Mutex
+	newLimiter  func(K) RateLimiter
+	idleTimeout time.Duration
+	now         func() time.Time
+	entries     map[K]*keyedLimiterEntry
+	lastSweep   time.Time
+}
+
+type keyedLimiterEntry struct {
+	limiter  RateLimiter
+	lastUsed time.Time
+}
+
+// NewKeyedLimiter returns a KeyedLimiter that builds limiters with
+// newLimiter. Limiters unused for idleTimeout are dropped; eviction happens
+// lazily during later calls, so no background goroutine is started. Pass the
+// same WithLimiterClock option here and to the limiters newLimiter creates
+// when testing with a fake clock. It panics if idleTimeout is not positive.
+//
+// Type Parameters:
+//
+//	K: The key type.
+//
+// Parameters:
+//
+//	newLimiter: Creates the limiter for a key the first time it is used.
+//	idleTimeout: How long a key's limiter is kept without use. It should
+//	             exceed the time the limiter needs to recover fully, or
+//	             eviction will reset a key's limit early.
+//	opts: An optional WithLimiterClock.
+//
+// Returns:
+//
+//	*KeyedLimiter[K]: An empty keyed limiter.
+func NewKeyedLimiter[K comparable](newLimiter func(key K) RateLimiter, idleTimeout time.Duration, opts ...LimiterOption) *KeyedLimiter[K] {
+	if idleTimeout <= 0 {
+		panic("concurrency.NewKeyedLimiter: idleTimeout must be positive")
+	}
+	cfg := newLimiterConfig(opts)
+	return &KeyedLimiter[K]{
+		newLimiter:  newLimiter,
+		idleTimeout: idleTimeout,
+		now:         cfg.clock.Now,
+		entries:     make(map[K]*keyedLimiterEntry),
+		lastSweep:   cfg.clock.Now(),
+	}
+}
+
+// Limiter returns the limiter for key, creating it if needed. Use it to
+// change one key's rate with SetRate.
+func (k *KeyedLimiter[K]) Limiter(key K) RateLimiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	now := k.now()
+	if now.Sub(k.lastSweep) >= k.idleTimeout {
+		for key, e := range k.entries {
+			if now.Sub(e.lastUsed) >= k.idleTimeout {
+				delete(k.entries, key)
+			}
+		}
+		k.lastSweep = now
+	}
+
+	e, ok := k.entries[key]
+	if !ok {
+		e = &keyedLimiterEntry{limiter: k.newLimiter(key)}
+		k.entries[key] = e
+	}
+	e.lastUsed = now
+	return e.limiter
+}
+
+// Allow reports whether an event for key may happen now.
+func (k *KeyedLimiter[K]) Allow(key K) bool {
+	return k.Limiter(key).Allow()
+}
+
+// Wait blocks until an event for key may happen or ctx is done.
+func (k *KeyedLimiter[K]) Wait(ctx context.Context, key K) error {
+	return k.Limiter(key).Wait(ctx)
+}
+
+// Reserve claims the next allowance for key without blocking.
+func (k *KeyedLimiter[K]) Reserve(key K) *Reservation {
+	return k.Limiter(key).Reserve()
+}
+
+// Len returns the number of keys with a live limiter, including idle ones
+// not yet evicted.
+func (k *KeyedLimiter[K]) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.entries)
+}
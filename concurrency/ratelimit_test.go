@@ -0,0 +1,230 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+// allowed counts how many of n immediate Allow calls succeed.
+func allowed(l concurrency.RateLimiter, n int) int {
+	count := 0
+	for range n {
+		if l.Allow() {
+			count++
+		}
+	}
+	return count
+}
+
+func TestTokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrency.NewTokenBucket(concurrency.PerSecond(10), 5, concurrency.WithLimiterClock(clock))
+
+	if got := allowed(b, 10); got != 5 {
+		t.Errorf("initial burst allowed %d, want 5", got)
+	}
+	clock.Advance(300 * time.Millisecond) // refills 3 tokens
+	if got := allowed(b, 10); got != 3 {
+		t.Errorf("after 300ms allowed %d, want 3", got)
+	}
+	clock.Advance(time.Hour) // refill is capped at the burst
+	if got := b.Tokens(); got != 5 {
+		t.Errorf("Tokens() after idle = %v, want 5", got)
+	}
+
+	allowed(b, 5)
+	r1, r2 := b.Reserve(), b.Reserve()
+	if !r1.OK() || r1.Delay() != 100*time.Millisecond || r2.Delay() != 200*time.Millisecond {
+		t.Errorf("reservation delays = %v, %v; want 100ms, 200ms", r1.Delay(), r2.Delay())
+	}
+	r2.Cancel()
+	if got := b.Tokens(); got != -1 {
+		t.Errorf("Tokens() after cancelling one of two borrowed = %v, want -1", got)
+	}
+}
+
+func TestTokenBucket_SetRate(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrency.NewTokenBucket(concurrency.PerSecond(1), 10, concurrency.WithLimiterClock(clock))
+	allowed(b, 10)
+
+	clock.Advance(2 * time.Second) // 2 tokens at the old rate
+	b.SetRate(concurrency.PerSecond(4))
+	clock.Advance(time.Second) // 4 more at the new rate
+	if got := allowed(b, 10); got != 6 {
+		t.Errorf("allowed %d after a rate change, want 6", got)
+	}
+	if r := b.Rate(); r != concurrency.PerSecond(4) {
+		t.Errorf("Rate() = %+v", r)
+	}
+}
+
+func TestLeakyBucket(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrency.NewLeakyBucket(concurrency.Rate{N: 1, Per: 100 * time.Millisecond}, 2, concurrency.WithLimiterClock(clock))
+
+	if !b.Allow() || b.Allow() {
+		t.Fatal("leaky bucket should allow exactly one immediate event")
+	}
+	r1, r2, r3 := b.Reserve(), b.Reserve(), b.Reserve()
+	if !r1.OK() || !r2.OK() || r3.OK() {
+		t.Fatalf("reservations OK = %v %v %v, want true true false", r1.OK(), r2.OK(), r3.OK())
+	}
+	if r1.Delay() != 100*time.Millisecond || r2.Delay() != 200*time.Millisecond {
+		t.Errorf("delays = %v, %v; want evenly spaced 100ms, 200ms", r1.Delay(), r2.Delay())
+	}
+	if err := b.Wait(context.Background()); !errors.Is(err, concurrency.ErrLimiterFull) {
+		t.Errorf("Wait() on a full bucket = %v, want ErrLimiterFull", err)
+	}
+
+	r2.Cancel()
+	if r := b.Reserve(); !r.OK() || r.Delay() != 200*time.Millisecond {
+		t.Errorf("reservation after cancel: OK=%v delay=%v, want the freed 200ms slot", r.OK(), r.Delay())
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	clock := newFakeClock()
+	w := concurrency.NewSlidingWindow(concurrency.Rate{N: 3, Per: time.Second}, concurrency.WithLimiterClock(clock))
+
+	if got := allowed(w, 5); got != 3 {
+		t.Errorf("allowed %d in the first window, want 3", got)
+	}
+	clock.Advance(999 * time.Millisecond)
+	if w.Allow() {
+		t.Error("events are still inside the window")
+	}
+	clock.Advance(time.Millisecond)
+	if got := allowed(w, 5); got != 3 {
+		t.Errorf("allowed %d once the window slid, want 3", got)
+	}
+
+	r := w.Reserve()
+	if r.Delay() != time.Second {
+		t.Errorf("Reserve().Delay() = %v, want 1s", r.Delay())
+	}
+	r.Cancel()
+	clock.Advance(time.Second)
+	if got := allowed(w, 5); got != 3 {
+		t.Errorf("allowed %d after cancelling a reservation, want 3", got)
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	checkGoroutineLeaks(t)
+	clock := newFakeClock()
+	b := concurrency.NewTokenBucket(concurrency.PerSecond(2), 1, concurrency.WithLimiterClock(clock))
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait() = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- b.Wait(context.Background()) }()
+	eventually(t, time.Second, func() bool { return clock.Waiters() == 1 }, "Wait is blocked on the clock")
+	select {
+	case <-done:
+		t.Fatal("Wait() returned before its token was available")
+	default:
+	}
+	clock.Advance(500 * time.Millisecond)
+	if err := <-done; err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { done <- b.Wait(ctx) }()
+	eventually(t, time.Second, func() bool { return clock.Waiters() == 1 }, "second Wait is blocked")
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled Wait() = %v, want context.Canceled", err)
+	}
+	// The cancelled wait returned its borrowed token.
+	if got := b.Tokens(); got != 0 {
+		t.Errorf("Tokens() = %v, want 0", got)
+	}
+}
+
+func TestKeyedLimiter(t *testing.T) {
+	clock := newFakeClock()
+	created := map[string]int{}
+	k := concurrency.NewKeyedLimiter(func(key string) concurrency.RateLimiter {
+		created[key]++
+		return concurrency.NewTokenBucket(concurrency.PerSecond(1), 2, concurrency.WithLimiterClock(clock))
+	}, time.Minute, concurrency.WithLimiterClock(clock))
+
+	if got := allowed(limiterFor(k, "alice"), 5); got != 2 {
+		t.Errorf("alice allowed %d, want 2", got)
+	}
+	if !k.Allow("bob") {
+		t.Error("bob has his own budget")
+	}
+	if k.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", k.Len())
+	}
+
+	clock.Advance(30 * time.Second)
+	k.Allow("bob") // bob stays active
+	clock.Advance(40 * time.Second)
+	k.Allow("carol") // triggers a sweep: alice idle 70s, bob 40s
+	if k.Len() != 2 || created["alice"] != 1 {
+		t.Errorf("Len() = %d after sweep, want 2 (bob, carol)", k.Len())
+	}
+	k.Allow("alice")
+	if created["alice"] != 2 {
+		t.Errorf("alice's limiter was created %d times, want 2 after eviction", created["alice"])
+	}
+
+	k.Limiter("bob").SetRate(concurrency.PerSecond(100))
+	if r := k.Limiter("bob").Rate(); r.N != 100 {
+		t.Errorf("per-key SetRate not applied: %+v", r)
+	}
+	if r := k.Reserve("dave"); !r.OK() || r.Delay() != 0 {
+		t.Error("Reserve() for a new key should be immediately usable")
+	}
+	if err := k.Wait(context.Background(), "erin"); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+}
+
+func limiterFor(k *concurrency.KeyedLimiter[string], key string) concurrency.RateLimiter {
+	return k.Limiter(key)
+}
+
+func TestRateLimiter_InvalidArgumentsPanic(t *testing.T) {
+	testCases := map[string]func(){
+		"ZeroRate":       func() { concurrency.NewTokenBucket(concurrency.Rate{}, 1) },
+		"ZeroBurst":      func() { concurrency.NewTokenBucket(concurrency.PerSecond(1), 0) },
+		"NegativeCap":    func() { concurrency.NewLeakyBucket(concurrency.PerSecond(1), -1) },
+		"WindowZeroPer":  func() { concurrency.NewSlidingWindow(concurrency.Rate{N: 1}) },
+		"SetRateInvalid": func() { concurrency.NewSlidingWindow(concurrency.PerSecond(1)).SetRate(concurrency.Rate{}) },
+		"KeyedIdleZero":  func() { concurrency.NewKeyedLimiter[int](nil, 0) },
+	}
+	for name, fn := range testCases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func ExampleNewTokenBucket() {
+	limiter := concurrency.NewTokenBucket(concurrency.PerSecond(100), 3)
+	for i := range 5 {
+		fmt.Println(i, limiter.Allow())
+	}
+	// Output:
+	// 0 true
+	// 1 true
+	// 2 true
+	// 3 false
+	// 4 false
+}
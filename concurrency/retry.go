@@ -0,0 +1,237 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// ErrRetriesExhausted is wrapped, together with the last attempt's error, by
+// the error Retry returns when it runs out of attempts or elapsed time.
+var ErrRetriesExhausted = errors.New("concurrency: retries exhausted")
+
+// Backoff computes how long to wait before the next attempt. attempt is the
+// number of the attempt that just failed, starting at 1, and prev is the
+// delay used before it (zero after the first attempt). Implementations must
+// be safe for concurrent use; the ones in this package are stateless.
+type Backoff interface {
+	Delay(attempt int, prev time.Duration) time.Duration
+}
+
+// BackoffFunc adapts an ordinary function to the Backoff interface.
+type BackoffFunc func(attempt int, prev time.Duration) time.Duration
+
+// Delay returns f(attempt, prev).
+func (f BackoffFunc) Delay(attempt int, prev time.Duration) time.Duration {
+	return f(attempt, prev)
+}
+
+// ConstantBackoff waits the same duration d between every attempt.
+func ConstantBackoff(d time.Duration) Backoff {
+	return BackoffFunc(func(int, time.Duration) time.Duration { return d })
+}
+
+// ExponentialBackoff waits initial after the first failure and doubles the
+// wait after each further failure, never exceeding limit. It panics if
+// initial is negative or limit is less than initial.
+func ExponentialBackoff(initial, limit time.Duration) Backoff {
+	if initial < 0 || limit < initial {
+		panic("concurrency.ExponentialBackoff: need 0 <= initial <= limit")
+	}
+	return BackoffFunc(func(attempt int, _ time.Duration) time.Duration {
+		d := initial
+		for i := 1; i < attempt && d < limit; i++ {
+			d *= 2
+		}
+		return min(d, limit)
+	})
+}
+
+// DecorrelatedJitterBackoff picks each wait at random between base and three
+// times the previous wait, capped at limit. Spreading retries out this way
+// keeps many clients that failed together from retrying in lockstep. It
+// panics if base is not positive or limit is less than base.
+func DecorrelatedJitterBackoff(base, limit time.Duration) Backoff {
+	if base <= 0 || limit < base {
+		panic("concurrency.DecorrelatedJitterBackoff: need 0 < base <= limit")
+	}
+	return BackoffFunc(func(_ int, prev time.Duration) time.Duration {
+		upper := max(base, min(prev*3, limit))
+		if upper == base {
+			return base
+		}
+		return base + rand.N(upper-base+1)
+	})
+}
+
+// Attempt describes a finished attempt, as passed to RetryPolicy.OnAttempt.
+type Attempt struct {
+	// Number is the attempt number, starting at 1.
+	Number int
+	// Err is the error the attempt returned, or nil if it succeeded.
+	Err error
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+	// Delay is the wait before the next attempt, or zero if there will be no
+	// further attempt.
+	Delay time.Duration
+}
+
+// RetryPolicy configures Retry. The zero value retries every error
+// immediately until fn succeeds or ctx is done.
+type RetryPolicy struct {
+	// MaxAttempts limits the total number of attempts, including the first.
+	// Zero or negative means no limit.
+	MaxAttempts int
+	// MaxElapsed gives up once another wait would end more than MaxElapsed
+	// after the first attempt started. Zero or negative means no limit.
+	MaxElapsed time.Duration
+	// Backoff computes the wait between attempts. Nil means no wait.
+	Backoff Backoff
+	// Retryable reports whether an error is worth retrying. Nil treats every
+	// error as retryable. Errors wrapped with Permanent are never retried.
+	Retryable func(error) bool
+	// OnAttempt, if set, is called after every attempt, for logging and
+	// metrics.
+	OnAttempt func(Attempt)
+	// Clock measures elapsed time and times the waits between attempts,
+	// using its NewTimer. Nil means clock.Real(). Tests can substitute a
+	// clock.FakeClock.
+	Clock clock.Clock
+}
+
+// permanentError marks an error that Retry must not retry.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry stops immediately and returns err
+// unwrapped, regardless of RetryPolicy.Retryable. Permanent(nil) is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// sleepContext waits on clk for d or until ctx is done.
+func sleepContext(ctx context.Context, clk clock.Clock, d time.Duration) error {
+	if d <= 0 || ctx.Err() != nil {
+		return ctx.Err()
+	}
+	timer := clk.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Retry calls fn until it succeeds, returns a non-retryable error, or the
+// policy's limits are reached, waiting between attempts as the policy's
+// Backoff dictates.
+//
+// Type Parameters:
+//
+//	T: The result type of fn.
+//
+// Parameters:
+//
+//	ctx: Passed to every attempt. Retry stops waiting and returns when ctx
+//	     is done.
+//	policy: The retry limits, backoff, error classification and hooks.
+//	fn: The operation to attempt.
+//
+// Returns:
+//
+//	T: The result of the first successful attempt, or the zero value.
+//	error: nil on success. The unwrapped error for Permanent or
+//	       non-retryable errors. An error wrapping both ErrRetriesExhausted
+//	       and the last error when the limits are reached. An error wrapping
+//	       both ctx.Err() and the last error when ctx ends a wait.
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	clk := clock.OrReal(policy.Clock)
+	now := clk.Now
+
+	var (
+		zero  T
+		delay time.Duration
+	)
+	start := now()
+	for attempt := 1; ; attempt++ {
+		value, err := fn(ctx)
+		if err == nil {
+			policy.report(Attempt{Number: attempt, Elapsed: now().Sub(start)})
+			return value, nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			policy.report(Attempt{Number: attempt, Err: err, Elapsed: now().Sub(start)})
+			return zero, perm.err
+		}
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			policy.report(Attempt{Number: attempt, Err: err, Elapsed: now().Sub(start)})
+			return zero, err
+		}
+
+		elapsed := now().Sub(start)
+		if policy.Backoff != nil {
+			delay = policy.Backoff.Delay(attempt, delay)
+		}
+		exhausted := (policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts) ||
+			(policy.MaxElapsed > 0 && elapsed+delay > policy.MaxElapsed)
+		if exhausted {
+			policy.report(Attempt{Number: attempt, Err: err, Elapsed: elapsed})
+			return zero, fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, attempt, err)
+		}
+
+		policy.report(Attempt{Number: attempt, Err: err, Elapsed: elapsed, Delay: delay})
+		if sleepErr := sleepContext(ctx, clk, delay); sleepErr != nil {
+			return zero, fmt.Errorf("concurrency.Retry: %w after %d attempts: %w", sleepErr, attempt, err)
+		}
+	}
+}
+
+func (p *RetryPolicy) report(a Attempt) {
+	if p.OnAttempt != nil {
+		p.OnAttempt(a)
+	}
+}
+
+// RetryEach adapts a context-aware per-element function into the
+// func(T) (R, error) shape accepted by functional.MapErr, retrying each
+// element independently under policy:
+//
+//	results, err := functional.MapErr(ids, concurrency.RetryEach(ctx, policy, fetch))
+//
+// Type Parameters:
+//
+//	T: The element type.
+//	R: The result type.
+//
+// Parameters:
+//
+//	ctx: Passed to every attempt and bounds every element's retries.
+//	policy: The retry policy applied to each element.
+//	fn: The per-element operation.
+//
+// Returns:
+//
+//	func(T) (R, error): A function that runs Retry for one element.
+func RetryEach[T, R any](ctx context.Context, policy RetryPolicy, fn func(ctx context.Context, element T) (R, error)) func(T) (R, error) {
+	return func(element T) (R, error) {
+		return Retry(ctx, policy, func(ctx context.Context) (R, error) {
+			return fn(ctx, element)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+	"github.com/JackovAlltrades/go-generics/concurrency"
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+var errFlaky = errors.New("flaky")
+
+// fakeRetryClock lets Retry run without real sleeps: every timer records the
+// requested delay, advances the clock by it and fires at once.
+type fakeRetryClock struct {
+	*clock.FakeClock
+	sleeps []time.Duration
+}
+
+func (c *fakeRetryClock) NewTimer(d time.Duration) clock.Timer {
+	c.sleeps = append(c.sleeps, d)
+	c.Advance(d)
+	return c.FakeClock.NewTimer(0)
+}
+
+func (c *fakeRetryClock) policy(p concurrency.RetryPolicy) concurrency.RetryPolicy {
+	c.FakeClock = clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	p.Clock = c
+	return p
+}
+
+// failTimes returns an operation that fails n times before succeeding.
+func failTimes(n int, err error) (func(context.Context) (string, error), *int) {
+	calls := 0
+	return func(context.Context) (string, error) {
+		calls++
+		if calls <= n {
+			return "", err
+		}
+		return "ok", nil
+	}, &calls
+}
+
+func TestRetry_SucceedsAfterFailures(t *testing.T) {
+	var clock fakeRetryClock
+	var attempts []concurrency.Attempt
+	policy := clock.policy(concurrency.RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     concurrency.ExponentialBackoff(100*time.Millisecond, time.Second),
+		OnAttempt:   func(a concurrency.Attempt) { attempts = append(attempts, a) },
+	})
+	fn, calls := failTimes(3, errFlaky)
+
+	got, err := concurrency.Retry(context.Background(), policy, fn)
+	if err != nil || got != "ok" {
+		t.Fatalf("Retry() = (%q, %v), want (ok, nil)", got, err)
+	}
+	if *calls != 4 {
+		t.Errorf("fn called %d times, want 4", *calls)
+	}
+	wantSleeps := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	if !reflect.DeepEqual(clock.sleeps, wantSleeps) {
+		t.Errorf("sleeps = %v, want %v", clock.sleeps, wantSleeps)
+	}
+	if len(attempts) != 4 || attempts[3].Err != nil || attempts[3].Elapsed != 700*time.Millisecond || attempts[0].Delay != 100*time.Millisecond {
+		t.Errorf("attempts = %+v", attempts)
+	}
+}
+
+func TestRetry_MaxAttemptsExhausted(t *testing.T) {
+	var clock fakeRetryClock
+	policy := clock.policy(concurrency.RetryPolicy{MaxAttempts: 3, Backoff: concurrency.ConstantBackoff(time.Second)})
+	fn, calls := failTimes(10, errFlaky)
+
+	_, err := concurrency.Retry(context.Background(), policy, fn)
+	if !errors.Is(err, concurrency.ErrRetriesExhausted) || !errors.Is(err, errFlaky) {
+		t.Errorf("Retry() error = %v, want ErrRetriesExhausted wrapping errFlaky", err)
+	}
+	if *calls != 3 || len(clock.sleeps) != 2 {
+		t.Errorf("calls = %d, sleeps = %v; want 3 calls and 2 sleeps", *calls, clock.sleeps)
+	}
+}
+
+func TestRetry_MaxElapsed(t *testing.T) {
+	var clock fakeRetryClock
+	policy := clock.policy(concurrency.RetryPolicy{
+		MaxElapsed: 1500 * time.Millisecond,
+		Backoff:    concurrency.ExponentialBackoff(250*time.Millisecond, 10*time.Second),
+	})
+	fn, calls := failTimes(10, errFlaky)
+
+	_, err := concurrency.Retry(context.Background(), policy, fn)
+	if !errors.Is(err, concurrency.ErrRetriesExhausted) {
+		t.Fatalf("Retry() error = %v, want ErrRetriesExhausted", err)
+	}
+	// Waits of 250ms and 500ms fit; the next 1s wait would end at 1.75s.
+	if *calls != 3 || !reflect.DeepEqual(clock.sleeps, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond}) {
+		t.Errorf("calls = %d, sleeps = %v", *calls, clock.sleeps)
+	}
+}
+
+func TestRetry_ErrorClassification(t *testing.T) {
+	errBadRequest := errors.New("bad request")
+
+	t.Run("Permanent", func(t *testing.T) {
+		var clock fakeRetryClock
+		fn, calls := failTimes(10, concurrency.Permanent(errBadRequest))
+		_, err := concurrency.Retry(context.Background(), clock.policy(concurrency.RetryPolicy{}), fn)
+		if err != errBadRequest || *calls != 1 {
+			t.Errorf("Retry() = %v after %d calls, want the unwrapped error after 1", err, *calls)
+		}
+	})
+
+	t.Run("WrappedPermanent", func(t *testing.T) {
+		var clock fakeRetryClock
+		fn, calls := failTimes(10, fmt.Errorf("fetch: %w", concurrency.Permanent(errBadRequest)))
+		_, err := concurrency.Retry(context.Background(), clock.policy(concurrency.RetryPolicy{}), fn)
+		if !errors.Is(err, errBadRequest) || *calls != 1 {
+			t.Errorf("Retry() = %v after %d calls, want errBadRequest after 1", err, *calls)
+		}
+	})
+
+	t.Run("RetryablePredicate", func(t *testing.T) {
+		var clock fakeRetryClock
+		policy := clock.policy(concurrency.RetryPolicy{
+			Retryable: func(err error) bool { return errors.Is(err, errFlaky) },
+		})
+		calls := 0
+		_, err := concurrency.Retry(context.Background(), policy, func(context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, errFlaky
+			}
+			return 0, errBadRequest
+		})
+		if err != errBadRequest || calls != 3 {
+			t.Errorf("Retry() = %v after %d calls, want errBadRequest after 3", err, calls)
+		}
+	})
+
+	if concurrency.Permanent(nil) != nil {
+		t.Error("Permanent(nil) should be nil")
+	}
+}
+
+func TestRetry_ContextCancelledDuringWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	fn, _ := failTimes(10, errFlaky)
+
+	start := time.Now()
+	_, err := concurrency.Retry(ctx, concurrency.RetryPolicy{Backoff: concurrency.ConstantBackoff(time.Hour)}, fn)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errFlaky) {
+		t.Errorf("Retry() error = %v, want DeadlineExceeded wrapping errFlaky", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Retry() did not stop waiting when ctx expired")
+	}
+}
+
+func TestBackoffPolicies(t *testing.T) {
+	exp := concurrency.ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	var got []time.Duration
+	for attempt := 1; attempt <= 5; attempt++ {
+		got = append(got, exp.Delay(attempt, 0))
+	}
+	want := []time.Duration{10, 20, 40, 50, 50}
+	for i := range want {
+		want[i] *= time.Millisecond
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExponentialBackoff delays = %v, want %v", got, want)
+	}
+
+	base, limit := 10*time.Millisecond, 200*time.Millisecond
+	jitter := concurrency.DecorrelatedJitterBackoff(base, limit)
+	prev := time.Duration(0)
+	for attempt := 1; attempt <= 50; attempt++ {
+		d := jitter.Delay(attempt, prev)
+		if d < base || d > limit || (prev > 0 && d > 3*prev) {
+			t.Fatalf("attempt %d: delay %v outside [%v, min(3*%v, %v)]", attempt, d, base, prev, limit)
+		}
+		prev = d
+	}
+
+	for name, fn := range map[string]func(){
+		"ExponentialNegative": func() { concurrency.ExponentialBackoff(-1, 0) },
+		"ExponentialLimit":    func() { concurrency.ExponentialBackoff(2, 1) },
+		"JitterZeroBase":      func() { concurrency.DecorrelatedJitterBackoff(0, 1) },
+		"JitterLimit":         func() { concurrency.DecorrelatedJitterBackoff(2, 1) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func TestRetryEach_WithMapErr(t *testing.T) {
+	var clock fakeRetryClock
+	policy := clock.policy(concurrency.RetryPolicy{MaxAttempts: 3})
+	failures := map[int]int{2: 2, 3: 5} // id 2 recovers, id 3 never does
+	fetch := func(_ context.Context, id int) (string, error) {
+		if failures[id] > 0 {
+			failures[id]--
+			return "", errFlaky
+		}
+		return "item-" + strconv.Itoa(id), nil
+	}
+
+	got, err := functional.MapErr([]int{1, 2}, concurrency.RetryEach(context.Background(), policy, fetch))
+	if err != nil || !reflect.DeepEqual(got, []string{"item-1", "item-2"}) {
+		t.Errorf("MapErr(RetryEach) = (%v, %v)", got, err)
+	}
+	if _, err := functional.MapErr([]int{3}, concurrency.RetryEach(context.Background(), policy, fetch)); !errors.Is(err, concurrency.ErrRetriesExhausted) {
+		t.Errorf("MapErr(RetryEach) error = %v, want ErrRetriesExhausted", err)
+	}
+}
+
+func ExampleRetry() {
+	calls := 0
+	policy := concurrency.RetryPolicy{
+		MaxAttempts: 4,
+		Backoff:     concurrency.ExponentialBackoff(time.Millisecond, 10*time.Millisecond),
+		OnAttempt: func(a concurrency.Attempt) {
+			fmt.Printf("attempt %d: err=%v next wait=%v\n", a.Number, a.Err, a.Delay)
+		},
+	}
+	v, err := concurrency.Retry(context.Background(), policy, func(context.Context) (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, errors.New("unavailable")
+		}
+		return 42, nil
+	})
+	fmt.Println(v, err)
+	// Output:
+	// attempt 1: err=unavailable next wait=1ms
+	// attempt 2: err=unavailable next wait=2ms
+	// attempt 3: err=<nil> next wait=0s
+	// 42 <nil>
+}
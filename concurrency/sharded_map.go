@@ -0,0 +1,310 @@
+package concurrency
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// ShardOption configures a ShardedMap.
+type ShardOption func(*shardConfig)
+
+type shardConfig struct {
+	shards int
+}
+
+// WithShardCount sets the number of shards, rounded up to a power of two.
+// More shards reduce lock contention at the cost of memory and slower bulk
+// operations. It panics if n is not positive. The default is four shards per
+// GOMAXPROCS.
+func WithShardCount(n int) ShardOption {
+	if n <= 0 {
+		panic("concurrency.WithShardCount: n must be positive")
+	}
+	return func(c *shardConfig) { c.shards = n }
+}
+
+// ShardedMap is a typed concurrent map that spreads its keys over several
+// independently locked shards, so writers to different shards do not contend.
+// It suits write-heavy workloads where sync.Map, which is optimised for keys
+// written once and read many times, performs poorly.
+//
+// Single-key operations are atomic. Bulk operations (Len, Keys, Values,
+// Range, Snapshot, Filter, ShardedGroupBy) visit the shards one at a time,
+// each under its lock: what they see of each shard is a consistent snapshot,
+// but writes to other shards may interleave, so the whole is not a
+// point-in-time snapshot of the map.
+type ShardedMap[K comparable, V any] struct {
+	shards []mapShard[K, V]
+	mask   uint64
+	hash   func(K) uint64
+}
+
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+	_  [32]byte // pad to 64 bytes so neighbouring shards do not share a cache line
+}
+
+// NewShardedMap returns an empty ShardedMap that hashes keys with
+// hash/maphash.
+func NewShardedMap[K comparable, V any](opts ...ShardOption) *ShardedMap[K, V] {
+	seed := maphash.MakeSeed()
+	return NewShardedMapFunc[K, V](func(k K) uint64 { return maphash.Comparable(seed, k) }, opts...)
+}
+
+// NewShardedMapFunc returns an empty ShardedMap that assigns keys to shards
+// with a custom hash function, for key types with a cheaper or better
+// distributed hash than the default.
+//
+// Type Parameters:
+//
+//	K: The key type.
+//	V: The value type.
+//
+// Parameters:
+//
+//	hash: Maps a key to a hash. Equal keys must have equal hashes; the low
+//	      bits select the shard, so they should be well distributed.
+//	opts: An optional WithShardCount.
+//
+// Returns:
+//
+//	*ShardedMap[K, V]: An empty map.
+func NewShardedMapFunc[K comparable, V any](hash func(K) uint64, opts ...ShardOption) *ShardedMap[K, V] {
+	cfg := shardConfig{shards: 4 * runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	n := 1 << bits.Len(uint(cfg.shards-1)) // round up to a power of two
+	m := &ShardedMap[K, V]{shards: make([]mapShard[K, V], n), mask: uint64(n - 1), hash: hash}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+func (m *ShardedMap[K, V]) shard(key K) *mapShard[K, V] {
+	return &m.shards[m.hash(key)&m.mask]
+}
+
+// ShardCount returns the number of shards.
+func (m *ShardedMap[K, V]) ShardCount() int {
+	return len(m.shards)
+}
+
+// Load returns the value stored for key and whether it was present.
+func (m *ShardedMap[K, V]) Load(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+// Store sets the value for key.
+func (m *ShardedMap[K, V]) Store(key K, value V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = value
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise it
+// stores and returns value. loaded reports whether the value was present.
+func (m *ShardedMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// LoadAndDelete deletes key, returning its previous value if any.
+func (m *ShardedMap[K, V]) LoadAndDelete(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	delete(s.m, key)
+	return v, ok
+}
+
+// Delete deletes key.
+func (m *ShardedMap[K, V]) Delete(key K) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}
+
+// Compute atomically updates key: fn receives the current value, if any, and
+// returns the new value and whether to keep it. Returning keep == false
+// deletes key. fn runs with the key's shard locked, so it must be quick and
+// must not call other methods of m.
+//
+// Parameters:
+//
+//	key: The key to update.
+//	fn: Computes the new value from the current one; loaded reports
+//	    whether key was present.
+//
+// Returns:
+//
+//	V: The new value, or the zero value if key was deleted.
+//	bool: Whether key is present after the update.
+func (m *ShardedMap[K, V]) Compute(key K, fn func(current V, loaded bool) (newValue V, keep bool)) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, loaded := s.m[key]
+	v, keep := fn(current, loaded)
+	if !keep {
+		delete(s.m, key)
+		var zero V
+		return zero, false
+	}
+	s.m[key] = v
+	return v, true
+}
+
+// Len returns the number of keys, summed shard by shard.
+func (m *ShardedMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Clear deletes every key, shard by shard.
+func (m *ShardedMap[K, V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.Lock()
+		clear(s.m)
+		s.mu.Unlock()
+	}
+}
+
+// eachShard calls fn with every shard's map while holding its read lock.
+func (m *ShardedMap[K, V]) eachShard(fn func(map[K]V)) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		fn(s.m)
+		s.mu.RUnlock()
+	}
+}
+
+// Keys returns the keys in no particular order.
+func (m *ShardedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.eachShard(func(sm map[K]V) {
+		for k := range sm {
+			keys = append(keys, k)
+		}
+	})
+	return keys
+}
+
+// Values returns the values in no particular order.
+func (m *ShardedMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	m.eachShard(func(sm map[K]V) {
+		for _, v := range sm {
+			values = append(values, v)
+		}
+	})
+	return values
+}
+
+// Snapshot copies the map into a plain map, which can then be used with the
+// functional package's map helpers.
+func (m *ShardedMap[K, V]) Snapshot() map[K]V {
+	out := make(map[K]V, m.Len())
+	m.eachShard(func(sm map[K]V) {
+		for k, v := range sm {
+			out[k] = v
+		}
+	})
+	return out
+}
+
+// Filter returns a plain map of the entries that satisfy pred. pred runs with
+// a shard read-locked, so it must not modify m.
+func (m *ShardedMap[K, V]) Filter(pred func(key K, value V) bool) map[K]V {
+	out := make(map[K]V)
+	m.eachShard(func(sm map[K]V) {
+		for k, v := range sm {
+			if pred(k, v) {
+				out[k] = v
+			}
+		}
+	})
+	return out
+}
+
+// Range calls fn for each entry until fn returns false. Each shard is copied
+// under its lock and fn runs without any lock held, so fn may read and write
+// m; entries it writes may or may not be visited.
+func (m *ShardedMap[K, V]) Range(fn func(key K, value V) bool) {
+	var batch []mapEntry[K, V]
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		batch = batch[:0]
+		for k, v := range s.m {
+			batch = append(batch, mapEntry[K, V]{k, v})
+		}
+		s.mu.RUnlock()
+		for _, e := range batch {
+			if !fn(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+type mapEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// ShardedGroupBy groups the values of a ShardedMap by a classifier, the
+// concurrent counterpart of functional.GroupBy. Each shard is read under its
+// lock.
+//
+// Type Parameters:
+//
+//	K: The map's key type.
+//	V: The map's value type.
+//	G: The group key type.
+//
+// Parameters:
+//
+//	m: The map to group.
+//	classifier: Returns the group of an entry. It runs with a shard
+//	            read-locked, so it must not modify m.
+//
+// Returns:
+//
+//	map[G][]V: The values in each group, in no particular order. An empty,
+//	           non-nil map if m is empty.
+func ShardedGroupBy[K comparable, V any, G comparable](m *ShardedMap[K, V], classifier func(key K, value V) G) map[G][]V {
+	groups := make(map[G][]V)
+	m.eachShard(func(sm map[K]V) {
+		for k, v := range sm {
+			g := classifier(k, v)
+			groups[g] = append(groups[g], v)
+		}
+	})
+	return groups
+}
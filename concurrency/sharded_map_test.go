@@ -0,0 +1,271 @@
+package concurrency_test
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+func TestShardedMap_Basics(t *testing.T) {
+	m := concurrency.NewShardedMap[string, int](concurrency.WithShardCount(5))
+	if m.ShardCount() != 8 {
+		t.Errorf("ShardCount() = %d, want 5 rounded up to 8", m.ShardCount())
+	}
+
+	m.Store("a", 1)
+	if v, ok := m.Load("a"); v != 1 || !ok {
+		t.Errorf("Load(a) = (%d, %v)", v, ok)
+	}
+	if _, ok := m.Load("missing"); ok {
+		t.Error("Load(missing) reported present")
+	}
+	if v, loaded := m.LoadOrStore("a", 9); v != 1 || !loaded {
+		t.Errorf("LoadOrStore(existing) = (%d, %v), want (1, true)", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("b", 2); v != 2 || loaded {
+		t.Errorf("LoadOrStore(new) = (%d, %v), want (2, false)", v, loaded)
+	}
+	if v, ok := m.LoadAndDelete("b"); v != 2 || !ok {
+		t.Errorf("LoadAndDelete(b) = (%d, %v)", v, ok)
+	}
+	m.Delete("a")
+	if m.Len() != 0 {
+		t.Errorf("Len() = %d after deletes, want 0", m.Len())
+	}
+}
+
+func TestShardedMap_Compute(t *testing.T) {
+	m := concurrency.NewShardedMap[string, int]()
+	incr := func(cur int, _ bool) (int, bool) { return cur + 1, true }
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				m.Compute("hits", incr)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Load("hits"); v != 8000 {
+		t.Errorf("concurrent Compute() count = %d, want 8000", v)
+	}
+
+	v, ok := m.Compute("hits", func(int, bool) (int, bool) { return 0, false })
+	if v != 0 || ok || m.Len() != 0 {
+		t.Errorf("Compute() returning keep=false = (%d, %v), Len() = %d; want deletion", v, ok, m.Len())
+	}
+	m.Compute("new", func(cur int, loaded bool) (int, bool) {
+		if loaded {
+			t.Error("Compute() on a missing key reported loaded")
+		}
+		return 5, true
+	})
+}
+
+func TestShardedMap_BulkHelpers(t *testing.T) {
+	m := concurrency.NewShardedMap[int, string](concurrency.WithShardCount(4))
+	for i := range 20 {
+		m.Store(i, strconv.Itoa(i))
+	}
+
+	keys := m.Keys()
+	sort.Ints(keys)
+	if len(keys) != 20 || keys[0] != 0 || keys[19] != 19 {
+		t.Errorf("Keys() = %v", keys)
+	}
+	if len(m.Values()) != 20 {
+		t.Errorf("Values() has %d entries, want 20", len(m.Values()))
+	}
+	snap := m.Snapshot()
+	if len(snap) != 20 || snap[7] != "7" {
+		t.Errorf("Snapshot() = %v", snap)
+	}
+
+	evens := m.Filter(func(k int, _ string) bool { return k%2 == 0 })
+	if len(evens) != 10 {
+		t.Errorf("Filter() kept %d entries, want 10", len(evens))
+	}
+
+	groups := concurrency.ShardedGroupBy(m, func(k int, _ string) string {
+		if k < 10 {
+			return "small"
+		}
+		return "large"
+	})
+	if len(groups["small"]) != 10 || len(groups["large"]) != 10 {
+		t.Errorf("ShardedGroupBy() = %v", groups)
+	}
+
+	// Range may modify the map while iterating.
+	visited := 0
+	m.Range(func(k int, _ string) bool {
+		visited++
+		m.Delete(k)
+		return true
+	})
+	if visited != 20 || m.Len() != 0 {
+		t.Errorf("Range() visited %d and left %d entries", visited, m.Len())
+	}
+
+	m.Store(1, "x")
+	m.Store(2, "y")
+	stops := 0
+	m.Range(func(int, string) bool { stops++; return false })
+	if stops != 1 {
+		t.Errorf("Range() ignored false, visited %d", stops)
+	}
+	m.Clear()
+	if m.Len() != 0 {
+		t.Error("Clear() left entries behind")
+	}
+}
+
+func TestShardedMap_CustomHasher(t *testing.T) {
+	m := concurrency.NewShardedMapFunc[int, int](func(k int) uint64 { return uint64(k) }, concurrency.WithShardCount(4))
+	for i := range 100 {
+		m.Store(i, i*i)
+	}
+	if v, _ := m.Load(9); v != 81 || m.Len() != 100 {
+		t.Errorf("custom-hashed map Load(9) = %d, Len() = %d", v, m.Len())
+	}
+
+	got := concurrency.ShardedGroupBy(concurrency.NewShardedMap[int, int](), func(int, int) bool { return true })
+	if got == nil || len(got) != 0 {
+		t.Errorf("ShardedGroupBy(empty) = %#v, want an empty non-nil map", got)
+	}
+}
+
+func TestShardedMap_ConcurrentMixedUse(t *testing.T) {
+	m := concurrency.NewShardedMap[int, int]()
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2000 {
+				k := (w*2000 + i) % 500
+				switch i % 4 {
+				case 0:
+					m.Store(k, i)
+				case 1:
+					m.Load(k)
+				case 2:
+					m.Delete(k)
+				default:
+					m.Range(func(int, int) bool { return false })
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(m.Snapshot()); got != m.Len() {
+		t.Errorf("Snapshot() size %d != Len() %d once quiescent", got, m.Len())
+	}
+}
+
+func TestWithShardCount_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithShardCount(0) did not panic")
+		}
+	}()
+	concurrency.WithShardCount(0)
+}
+
+func ExampleShardedMap() {
+	hits := concurrency.NewShardedMap[string, int]()
+	for _, path := range []string{"/", "/about", "/", "/"} {
+		hits.Compute(path, func(n int, _ bool) (int, bool) { return n + 1, true })
+	}
+	snap := hits.Snapshot()
+	fmt.Println(snap["/"], snap["/about"], hits.Len())
+	// Output:
+	// 3 1 2
+}
+
+// --- Benchmarks: ShardedMap vs sync.Map vs a RWMutex-guarded map ---
+
+const benchKeys = 1 << 12
+
+type rwMap struct {
+	mu sync.RWMutex
+	m  map[int]int
+}
+
+func (r *rwMap) load(k int) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.m[k]
+	return v, ok
+}
+
+func (r *rwMap) store(k, v int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.m[k] = v
+}
+
+// benchMix runs load and store with the given percentage of writes.
+func benchMix(b *testing.B, writePercent int, load func(int) (int, bool), store func(int, int)) {
+	for i := range benchKeys {
+		store(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			k := (i * 7919) & (benchKeys - 1)
+			if i%100 < writePercent {
+				store(k, i)
+			} else {
+				load(k)
+			}
+			i++
+		}
+	})
+}
+
+func benchmarkMaps(b *testing.B, writePercent int) {
+	b.Run("ShardedMap", func(b *testing.B) {
+		m := concurrency.NewShardedMap[int, int]()
+		benchMix(b, writePercent, m.Load, m.Store)
+	})
+	b.Run("SyncMap", func(b *testing.B) {
+		var m sync.Map
+		benchMix(b, writePercent, func(k int) (int, bool) {
+			v, ok := m.Load(k)
+			if !ok {
+				return 0, false
+			}
+			return v.(int), true
+		}, func(k, v int) { m.Store(k, v) })
+	})
+	b.Run("RWMutexMap", func(b *testing.B) {
+		m := &rwMap{m: make(map[int]int)}
+		benchMix(b, writePercent, m.load, m.store)
+	})
+}
+
+func BenchmarkMaps_WriteHeavy(b *testing.B) { benchmarkMaps(b, 75) }
+func BenchmarkMaps_Mixed(b *testing.B)      { benchmarkMaps(b, 25) }
+func BenchmarkMaps_ReadHeavy(b *testing.B)  { benchmarkMaps(b, 1) }
+
+func BenchmarkShardedMap_Snapshot(b *testing.B) {
+	m := concurrency.NewShardedMap[int, int]()
+	for i := range benchKeys {
+		m.Store(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if len(m.Snapshot()) != benchKeys {
+			b.Fatal("bad snapshot")
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/clock"
+)
+
+// GroupOption configures a Group.
+type GroupOption func(*groupConfig)
+
+type groupConfig struct {
+	ttl   time.Duration
+	clock clock.Clock
+}
+
+// WithResultTTL keeps each successful result for d after its call finishes,
+// so callers arriving shortly afterwards share it instead of starting a new
+// call. Errors are never kept. It panics if d is negative.
+func WithResultTTL(d time.Duration) GroupOption {
+	if d < 0 {
+		panic("concurrency.WithResultTTL: d must not be negative")
+	}
+	return func(c *groupConfig) { c.ttl = d }
+}
+
+// WithGroupClock replaces the clock used for result expiry, so tests can use
+// a clock.FakeClock. The default is clock.Real().
+func WithGroupClock(c clock.Clock) GroupOption {
+	return func(cfg *groupConfig) { cfg.clock = c }
+}
+
+// GroupResult is the outcome of a Group call, as delivered by DoChan.
+type GroupResult[V any] struct {
+	Value V
+	Err   error
+	// Shared reports whether the result was also given to other callers, or
+	// came from a call another caller started.
+	Shared bool
+}
+
+// Group suppresses duplicate calls: while a call for a key is in flight,
+// further callers for the same key wait for it and share its result instead
+// of starting their own. It is a typed counterpart of
+// golang.org/x/sync/singleflight.
+//
+// The zero value is ready to use and keeps no results after a call finishes;
+// use NewGroup to set a result TTL. A Group must not be copied after first
+// use.
+type Group[K comparable, V any] struct {
+	mu        sync.Mutex
+	cfg       groupConfig
+	calls     map[K]*flight[V]
+	lastSweep time.Time
+}
+
+type flight[V any] struct {
+	done     chan struct{} // closed once value and err are set
+	value    V
+	err      error
+	waiters  int // callers still waiting; guarded by Group.mu
+	dups     int // callers that joined after the first; guarded by Group.mu
+	finished bool
+	cancel   context.CancelFunc
+	expires  time.Time // when a finished, kept result expires
+}
+
+// NewGroup returns a Group configured by opts.
+func NewGroup[K comparable, V any](opts ...GroupOption) *Group[K, V] {
+	g := &Group[K, V]{}
+	for _, opt := range opts {
+		opt(&g.cfg)
+	}
+	return g
+}
+
+func (g *Group[K, V]) now() time.Time {
+	return clock.OrReal(g.cfg.clock).Now()
+}
+
+// Do runs fn for key unless a call for key is already in flight, or a kept
+// result has not yet expired, in which case it shares that result.
+//
+// fn runs on its own goroutine with a context that carries the values of the
+// first caller's ctx but not its cancellation. A caller whose ctx is done
+// stops waiting and gets ctx.Err(); the shared call is cancelled only when
+// every waiting caller has left. A panic in fn is recovered and reported to
+// all waiters as a *PanicError.
+//
+// Parameters:
+//
+//	ctx: Bounds how long this caller waits.
+//	key: Identifies duplicate calls.
+//	fn: The call to run.
+//
+// Returns:
+//
+//	V, error: The call's result, or ctx.Err() if this caller gave up.
+//	bool: Whether the result was shared with other callers.
+func (g *Group[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flight[V])
+	}
+	now := g.now()
+	g.sweepLocked(now)
+
+	if c, ok := g.calls[key]; ok {
+		if !c.finished {
+			c.waiters++
+			c.dups++
+			g.mu.Unlock()
+			return g.wait(ctx, key, c, true)
+		}
+		if now.Before(c.expires) {
+			g.mu.Unlock()
+			return c.value, c.err, true
+		}
+		delete(g.calls, key)
+	}
+
+	callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c := &flight[V]{done: make(chan struct{}), waiters: 1, cancel: cancel}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	go g.run(callCtx, key, c, fn)
+	return g.wait(ctx, key, c, false)
+}
+
+// DoChan is like Do but returns a channel that receives the result once it
+// is ready. The channel is buffered, so the caller may stop listening.
+func (g *Group[K, V]) DoChan(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) <-chan GroupResult[V] {
+	ch := make(chan GroupResult[V], 1)
+	go func() {
+		v, err, shared := g.Do(ctx, key, fn)
+		ch <- GroupResult[V]{Value: v, Err: err, Shared: shared}
+	}()
+	return ch
+}
+
+// Forget drops key's in-flight call or kept result, so the next Do for key
+// starts a new call. Callers already waiting still receive the old result.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
+
+func (g *Group[K, V]) run(ctx context.Context, key K, c *flight[V], fn func(context.Context) (V, error)) {
+	v, err := callSafely(func() (V, error) { return fn(ctx) })
+	c.cancel()
+
+	g.mu.Lock()
+	c.value, c.err, c.finished = v, err, true
+	if g.calls[key] == c {
+		if err == nil && g.cfg.ttl > 0 {
+			c.expires = g.now().Add(g.cfg.ttl)
+		} else {
+			delete(g.calls, key)
+		}
+	}
+	g.mu.Unlock()
+	close(c.done)
+}
+
+func (g *Group[K, V]) wait(ctx context.Context, key K, c *flight[V], joined bool) (V, error, bool) {
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared := joined || c.dups > 0
+		g.mu.Unlock()
+		return c.value, c.err, shared
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 && !c.finished {
+			c.cancel()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		var zero V
+		return zero, ctx.Err(), joined
+	}
+}
+
+// sweepLocked drops expired results, at most once per TTL.
+func (g *Group[K, V]) sweepLocked(now time.Time) {
+	if g.cfg.ttl <= 0 || now.Sub(g.lastSweep) < g.cfg.ttl {
+		return
+	}
+	for key, c := range g.calls {
+		if c.finished && !now.Before(c.expires) {
+			delete(g.calls, key)
+		}
+	}
+	g.lastSweep = now
+}
@@ -0,0 +1,208 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+// waitingContext closes waiting when Done is first called, which Group does
+// only once a caller has joined a call and starts waiting for it.
+type waitingContext struct {
+	context.Context
+	once    sync.Once
+	waiting chan struct{}
+}
+
+func (c *waitingContext) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.waiting) })
+	return c.Context.Done()
+}
+
+func TestGroup_SuppressesDuplicates(t *testing.T) {
+	checkGoroutineLeaks(t)
+	// A long TTL makes callers that arrive after completion share too.
+	g := concurrency.NewGroup[string, int](concurrency.WithResultTTL(time.Hour))
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]int, n)
+	shared := make([]bool, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, s := g.Do(context.Background(), "answer", fn)
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+			results[i], shared[i] = v, s
+		}()
+	}
+	eventually(t, time.Second, func() bool { return calls.Load() == 1 }, "call started")
+	// Callers that have not joined by now arrive after completion, and share
+	// the kept result instead.
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn called %d times, want 1", calls.Load())
+	}
+	for i := range n {
+		if results[i] != 42 || !shared[i] {
+			t.Errorf("caller %d got (%d, shared=%v), want (42, true)", i, results[i], shared[i])
+		}
+	}
+
+	// With no TTL, a finished call is not kept.
+	var plain concurrency.Group[string, int]
+	plain.Do(context.Background(), "answer", fn)
+	v, _, s := plain.Do(context.Background(), "answer", func(context.Context) (int, error) { return 7, nil })
+	if v != 7 || s {
+		t.Errorf("Do() after completion = (%d, shared=%v), want (7, false)", v, s)
+	}
+}
+
+func TestGroup_ErrorsAndPanics(t *testing.T) {
+	checkGoroutineLeaks(t)
+	var g concurrency.Group[int, string]
+	errBoom := errors.New("boom")
+
+	if _, err, _ := g.Do(context.Background(), 1, func(context.Context) (string, error) { return "", errBoom }); err != errBoom {
+		t.Errorf("Do() error = %v, want errBoom", err)
+	}
+	_, err, _ := g.Do(context.Background(), 2, func(context.Context) (string, error) { panic("kaboom") })
+	var pe *concurrency.PanicError
+	if !errors.As(err, &pe) || pe.Value != "kaboom" {
+		t.Errorf("Do() error = %v, want a PanicError", err)
+	}
+}
+
+func TestGroup_WaiterCancellation(t *testing.T) {
+	checkGoroutineLeaks(t)
+	var g concurrency.Group[string, int]
+	started := make(chan struct{})
+	callCancelled := make(chan struct{})
+	release := make(chan struct{})
+	var callCtx context.Context
+	fn := func(ctx context.Context) (int, error) {
+		callCtx = ctx
+		close(started)
+		select {
+		case <-ctx.Done():
+			close(callCancelled)
+			return 0, ctx.Err()
+		case <-release:
+			return 1, nil
+		}
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	waiting2 := &waitingContext{Context: ctx2, waiting: make(chan struct{})}
+	res1 := g.DoChan(ctx1, "k", fn)
+	<-started
+	res2 := g.DoChan(waiting2, "k", fn)
+	<-waiting2.waiting // the second caller has joined
+
+	// One waiter leaving does not cancel the shared call.
+	cancel1()
+	if r := <-res1; !errors.Is(r.Err, context.Canceled) {
+		t.Errorf("first waiter got %+v, want context.Canceled", r)
+	}
+	if err := callCtx.Err(); err != nil {
+		t.Fatalf("shared call was cancelled while a waiter remained: %v", err)
+	}
+
+	// The last waiter leaving cancels it.
+	cancel2()
+	if r := <-res2; !errors.Is(r.Err, context.Canceled) || !r.Shared {
+		t.Errorf("second waiter got %+v, want a shared context.Canceled", r)
+	}
+	select {
+	case <-callCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("shared call was not cancelled after every waiter left")
+	}
+	close(release)
+
+	// An abandoned call does not block new callers.
+	if v, err, _ := g.Do(context.Background(), "k", func(context.Context) (int, error) { return 2, nil }); v != 2 || err != nil {
+		t.Errorf("Do() after abandonment = (%d, %v), want (2, nil)", v, err)
+	}
+}
+
+func TestGroup_CallContextKeepsValues(t *testing.T) {
+	checkGoroutineLeaks(t)
+	type ctxKey struct{}
+	var g concurrency.Group[int, string]
+	ctx := context.WithValue(context.Background(), ctxKey{}, "trace-1")
+	v, _, _ := g.Do(ctx, 1, func(ctx context.Context) (string, error) {
+		return ctx.Value(ctxKey{}).(string), nil
+	})
+	if v != "trace-1" {
+		t.Errorf("call context value = %q, want trace-1", v)
+	}
+}
+
+func TestGroup_ResultTTLAndForget(t *testing.T) {
+	checkGoroutineLeaks(t)
+	clock := newFakeClock()
+	g := concurrency.NewGroup[string, int](concurrency.WithResultTTL(time.Second), concurrency.WithGroupClock(clock))
+	calls := 0
+	fn := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+	ctx := context.Background()
+
+	if v, _, s := g.Do(ctx, "k", fn); v != 1 || s {
+		t.Fatalf("first Do() = (%d, shared=%v)", v, s)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if v, _, s := g.Do(ctx, "k", fn); v != 1 || !s {
+		t.Errorf("Do() within TTL = (%d, shared=%v), want the kept result", v, s)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if v, _, _ := g.Do(ctx, "k", fn); v != 2 {
+		t.Errorf("Do() after TTL = %d, want a new call", v)
+	}
+	g.Forget("k")
+	if v, _, _ := g.Do(ctx, "k", fn); v != 3 {
+		t.Errorf("Do() after Forget = %d, want a new call", v)
+	}
+
+	errFn := func(context.Context) (int, error) { calls++; return 0, errFlaky }
+	g.Do(ctx, "err", errFn)
+	g.Do(ctx, "err", errFn)
+	if calls != 5 {
+		t.Errorf("errors should not be kept: %d calls, want 5", calls)
+	}
+}
+
+func ExampleGroup() {
+	var g concurrency.Group[string, string]
+	loads := 0
+	load := func(context.Context) (string, error) {
+		loads++
+		return "config-v2", nil
+	}
+
+	v, err, shared := g.Do(context.Background(), "config", load)
+	fmt.Println(v, err, shared, loads)
+	// Output:
+	// config-v2 <nil> false 1
+}
@@ -0,0 +1,227 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrorMode selects how a TaskGroup reacts to a failing task.
+type ErrorMode int
+
+const (
+	// FailFast cancels the group's context at the first failure, and Wait
+	// returns that failure. This is the default.
+	FailFast ErrorMode = iota
+	// CollectAll lets every task run to completion, and Wait returns all
+	// failures joined in submission order.
+	CollectAll
+)
+
+// TaskError reports the failure of one task in a TaskGroup.
+type TaskError struct {
+	// Index is the task's position in submission order, starting at 0.
+	Index int
+	// Err is the task's error: what it returned, a *PanicError if it
+	// panicked, or the context error if it never started because the group
+	// was cancelled.
+	Err error
+}
+
+// Error returns the task index and its error.
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("concurrency: task %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the task's error.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// TaskGroupOption configures a TaskGroup.
+type TaskGroupOption func(*taskGroupConfig)
+
+type taskGroupConfig struct {
+	limit int
+	mode  ErrorMode
+}
+
+// WithTaskLimit runs at most n tasks at a time; Go blocks while the limit is
+// reached. It panics if n is not positive. By default there is no limit.
+func WithTaskLimit(n int) TaskGroupOption {
+	if n <= 0 {
+		panic("concurrency.WithTaskLimit: n must be positive")
+	}
+	return func(c *taskGroupConfig) { c.limit = n }
+}
+
+// WithErrorMode sets how the group reacts to failures. The default is
+// FailFast.
+func WithErrorMode(mode ErrorMode) TaskGroupOption {
+	return func(c *taskGroupConfig) { c.mode = mode }
+}
+
+// TaskGroup runs related tasks concurrently and collects their typed results
+// in submission order. Unlike errgroup, a panicking task fails on its own
+// with a *PanicError instead of crashing the process.
+//
+// The group owns a context, derived from the one given to NewTaskGroup, that
+// is passed to every task and cancelled by a FailFast failure and by Wait.
+// Groups nest naturally: a group created from a task's context is cancelled
+// together with its parent.
+type TaskGroup[T any] struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	cfg    taskGroupConfig
+	sem    chan struct{} // nil without a limit
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	results  []T
+	errs     []error // per task, used in CollectAll mode
+	firstErr error
+	waited   bool
+}
+
+// NewTaskGroup returns an empty TaskGroup whose context derives from ctx.
+//
+// Type Parameters:
+//
+//	T: The result type of the group's tasks.
+//
+// Parameters:
+//
+//	ctx: The parent context. Cancelling it cancels the group's tasks.
+//	opts: Optional WithTaskLimit and WithErrorMode settings.
+//
+// Returns:
+//
+//	*TaskGroup[T]: A group ready for Go.
+func NewTaskGroup[T any](ctx context.Context, opts ...TaskGroupOption) *TaskGroup[T] {
+	var cfg taskGroupConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &TaskGroup[T]{ctx: ctx, cancel: cancel, cfg: cfg}
+	if cfg.limit > 0 {
+		g.sem = make(chan struct{}, cfg.limit)
+	}
+	return g
+}
+
+// Context returns the group's context, for creating nested groups or
+// checking whether the group has been cancelled. After a FailFast failure,
+// context.Cause reports the failing task's *TaskError.
+func (g *TaskGroup[T]) Context() context.Context {
+	return g.ctx
+}
+
+// Go starts fn on a new goroutine, blocking first while the task limit is
+// reached. If the group is cancelled before fn can start, fn is not called
+// and the task fails with the context's error. It panics if called after
+// Wait.
+func (g *TaskGroup[T]) Go(fn func(ctx context.Context) (T, error)) {
+	index := g.add()
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		case <-g.ctx.Done():
+			g.record(index, *new(T), g.ctx.Err())
+			return
+		}
+	}
+	g.start(index, fn)
+}
+
+// TryGo starts fn only if the task limit allows it right now, reporting
+// whether it did. Without a limit it always starts fn. It panics if called
+// after Wait.
+func (g *TaskGroup[T]) TryGo(fn func(ctx context.Context) (T, error)) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(g.add(), fn)
+	return true
+}
+
+// add reserves the next result slot.
+func (g *TaskGroup[T]) add() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.waited {
+		panic("concurrency.TaskGroup: Go called after Wait")
+	}
+	var zero T
+	g.results = append(g.results, zero)
+	g.errs = append(g.errs, nil)
+	return len(g.results) - 1
+}
+
+// start runs fn for the task at index; the caller holds a limit slot if the
+// group has a limit.
+func (g *TaskGroup[T]) start(index int, fn func(context.Context) (T, error)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		if err := g.ctx.Err(); err != nil {
+			g.record(index, *new(T), err)
+			return
+		}
+		v, err := callSafely(func() (T, error) { return fn(g.ctx) })
+		g.record(index, v, err)
+	}()
+}
+
+func (g *TaskGroup[T]) record(index int, v T, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.results[index] = v
+	if err == nil {
+		return
+	}
+	te := &TaskError{Index: index, Err: err}
+	g.errs[index] = te
+	if g.firstErr == nil {
+		g.firstErr = te
+		if g.cfg.mode == FailFast {
+			g.cancel(te)
+		}
+	}
+}
+
+// Wait waits for every task to finish, cancels the group's context, and
+// returns the results in submission order. A failed or skipped task leaves
+// the zero value, or whatever it returned alongside its error.
+//
+// Returns:
+//
+//	[]T: One result per task, in submission order; an empty slice if no
+//	     task was started.
+//	error: nil if every task succeeded. In FailFast mode, the first
+//	       *TaskError. In CollectAll mode, every *TaskError joined in
+//	       submission order.
+func (g *TaskGroup[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	g.cancel(nil)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.waited = true
+	results := g.results
+	if results == nil {
+		results = []T{}
+	}
+	if g.cfg.mode == CollectAll {
+		return results, errors.Join(g.errs...)
+	}
+	return results, g.firstErr
+}
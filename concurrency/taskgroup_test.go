@@ -0,0 +1,241 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JackovAlltrades/go-generics/concurrency"
+)
+
+func TestTaskGroup_ResultsInSubmissionOrder(t *testing.T) {
+	checkGoroutineLeaks(t)
+	g := concurrency.NewTaskGroup[string](context.Background())
+	// Each task waits for the next one, so they finish in reverse.
+	finished := make([]chan struct{}, 6)
+	for i := range finished {
+		finished[i] = make(chan struct{})
+	}
+	close(finished[5])
+	for i := range 5 {
+		g.Go(func(context.Context) (string, error) {
+			defer close(finished[i])
+			<-finished[i+1]
+			return fmt.Sprint("task-", i), nil
+		})
+	}
+	got, err := g.Wait()
+	want := []string{"task-0", "task-1", "task-2", "task-3", "task-4"}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Wait() = (%v, %v), want (%v, nil)", got, err, want)
+	}
+	if g.Context().Err() == nil {
+		t.Error("Wait() should cancel the group context")
+	}
+
+	empty, err := concurrency.NewTaskGroup[int](context.Background()).Wait()
+	if empty == nil || len(empty) != 0 || err != nil {
+		t.Errorf("Wait() on an empty group = (%#v, %v)", empty, err)
+	}
+}
+
+func TestTaskGroup_FailFast(t *testing.T) {
+	checkGoroutineLeaks(t)
+	g := concurrency.NewTaskGroup[int](context.Background())
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	g.Go(func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return -1, ctx.Err()
+	})
+	g.Go(func(context.Context) (int, error) {
+		<-started
+		return 0, errFlaky
+	})
+
+	got, err := g.Wait()
+	var te *concurrency.TaskError
+	if !errors.As(err, &te) || te.Index != 1 || !errors.Is(err, errFlaky) {
+		t.Fatalf("Wait() error = %v, want TaskError{Index: 1} wrapping errFlaky", err)
+	}
+	<-cancelled
+	if !reflect.DeepEqual(got, []int{-1, 0}) {
+		t.Errorf("results = %v, want [-1 0]", got)
+	}
+	if cause := context.Cause(g.Context()); !errors.Is(cause, errFlaky) {
+		t.Errorf("context cause = %v, want the failing task", cause)
+	}
+}
+
+func TestTaskGroup_CollectAll(t *testing.T) {
+	checkGoroutineLeaks(t)
+	errA, errB := errors.New("a"), errors.New("b")
+	g := concurrency.NewTaskGroup[int](context.Background(), concurrency.WithErrorMode(concurrency.CollectAll))
+	// Tasks finish in the order 2, 0, 1: errors are reported in submission
+	// order regardless, and the middle task runs after both failures.
+	doneA, doneB := make(chan struct{}), make(chan struct{})
+	g.Go(func(context.Context) (int, error) {
+		defer close(doneA)
+		<-doneB
+		return 0, errA
+	})
+	g.Go(func(ctx context.Context) (int, error) {
+		<-doneA
+		return 2, ctx.Err() // not cancelled by the other failures
+	})
+	g.Go(func(context.Context) (int, error) {
+		defer close(doneB)
+		return 0, errB
+	})
+
+	got, err := g.Wait()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("Wait() error = %v, want both errors", err)
+	}
+	if msg := err.Error(); msg != "concurrency: task 0: a\nconcurrency: task 2: b" {
+		t.Errorf("error message = %q, want errors in submission order", msg)
+	}
+	if got[1] != 2 {
+		t.Errorf("results = %v, want the middle task to complete", got)
+	}
+}
+
+func TestTaskGroup_PanicCapture(t *testing.T) {
+	checkGoroutineLeaks(t)
+	g := concurrency.NewTaskGroup[int](context.Background(), concurrency.WithErrorMode(concurrency.CollectAll))
+	g.Go(func(context.Context) (int, error) { return 1, nil })
+	g.Go(func(context.Context) (int, error) {
+		var m map[string]int
+		m["boom"] = 1 // nil map write panics
+		return 0, nil
+	})
+
+	got, err := g.Wait()
+	var pe *concurrency.PanicError
+	if !errors.As(err, &pe) || !strings.Contains(string(pe.Stack), "taskgroup_test.go") {
+		t.Fatalf("Wait() error = %v, want a PanicError with a stack trace", err)
+	}
+	if got[0] != 1 {
+		t.Errorf("results = %v, the healthy task should still succeed", got)
+	}
+}
+
+func TestTaskGroup_Limit(t *testing.T) {
+	checkGoroutineLeaks(t)
+	g := concurrency.NewTaskGroup[int](context.Background(), concurrency.WithTaskLimit(2))
+	var running, peak atomic.Int32
+	release, submitted := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(submitted)
+		for i := range 10 {
+			g.Go(func(context.Context) (int, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				<-release
+				running.Add(-1)
+				return i, nil
+			})
+		}
+	}()
+	eventually(t, time.Second, func() bool { return running.Load() == 2 }, "2 tasks running")
+	close(release)
+	<-submitted
+	got, err := g.Wait()
+	if err != nil || len(got) != 10 || got[9] != 9 {
+		t.Errorf("Wait() = (%v, %v)", got, err)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", p)
+	}
+}
+
+func TestTaskGroup_TryGo(t *testing.T) {
+	checkGoroutineLeaks(t)
+	g := concurrency.NewTaskGroup[int](context.Background(), concurrency.WithTaskLimit(1))
+	release := make(chan struct{})
+	if !g.TryGo(func(context.Context) (int, error) { <-release; return 1, nil }) {
+		t.Fatal("TryGo() with a free slot = false")
+	}
+	if g.TryGo(func(context.Context) (int, error) { return 2, nil }) {
+		t.Error("TryGo() at the limit = true")
+	}
+	close(release)
+	if got, _ := g.Wait(); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("Wait() = %v, want only the started task", got)
+	}
+}
+
+func TestTaskGroup_SkipsTasksAfterCancellation(t *testing.T) {
+	checkGoroutineLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	g := concurrency.NewTaskGroup[int](ctx, concurrency.WithTaskLimit(1))
+	cancel()
+
+	called := false
+	g.Go(func(context.Context) (int, error) { called = true; return 1, nil })
+	_, err := g.Wait()
+	if called || !errors.Is(err, context.Canceled) {
+		t.Errorf("task called=%v, err=%v; want skipped with context.Canceled", called, err)
+	}
+}
+
+func TestTaskGroup_NestedGroupsCancelChildren(t *testing.T) {
+	checkGoroutineLeaks(t)
+	parent := concurrency.NewTaskGroup[[]string](context.Background())
+	childStarted, childCancelled := make(chan struct{}), make(chan struct{})
+
+	parent.Go(func(ctx context.Context) ([]string, error) {
+		child := concurrency.NewTaskGroup[string](ctx)
+		child.Go(func(ctx context.Context) (string, error) {
+			close(childStarted)
+			<-ctx.Done()
+			close(childCancelled)
+			return "", ctx.Err()
+		})
+		return child.Wait()
+	})
+	parent.Go(func(context.Context) ([]string, error) {
+		<-childStarted
+		return nil, errFlaky
+	})
+
+	if _, err := parent.Wait(); !errors.Is(err, errFlaky) {
+		t.Errorf("parent Wait() = %v, want errFlaky", err)
+	}
+	waitClosed(t, childCancelled, "nested task")
+}
+
+func TestTaskGroup_GoAfterWaitPanics(t *testing.T) {
+	g := concurrency.NewTaskGroup[int](context.Background())
+	_, _ = g.Wait()
+	defer func() {
+		if recover() == nil {
+			t.Error("Go() after Wait() did not panic")
+		}
+	}()
+	g.Go(func(context.Context) (int, error) { return 0, nil })
+}
+
+func ExampleTaskGroup() {
+	g := concurrency.NewTaskGroup[int](context.Background(), concurrency.WithTaskLimit(4))
+	for _, word := range []string{"alpha", "be", "gamma", "delta"} {
+		g.Go(func(context.Context) (int, error) {
+			return len(word), nil
+		})
+	}
+	lengths, err := g.Wait()
+	fmt.Println(lengths, err)
+	// Output:
+	// [5 2 5 5] <nil>
+}
@@ -0,0 +1,345 @@
+package functional
+
+import (
+	"iter"
+	"math/big"
+)
+
+// The combinatorics generators are lazy: they return an iter.Seq that
+// computes each tuple on demand, so spaces far too large to hold in memory
+// can be explored, and iteration can stop early with break. Tuples are built
+// from element positions, so duplicate input values produce duplicate
+// tuples. Use the Count* helpers to size a space before iterating it.
+
+// CombinatoricsOption configures the combinatorics generators.
+type CombinatoricsOption func(*combinatoricsConfig)
+
+type combinatoricsConfig struct {
+	reuseBuffer bool
+}
+
+// ReuseBuffer makes a generator yield the same slice on every iteration,
+// overwriting its contents in place, so iteration does not allocate. The
+// yielded slice is only valid until the loop body returns; copy it (for
+// example with slices.Clone) to keep it.
+func ReuseBuffer() CombinatoricsOption {
+	return func(c *combinatoricsConfig) { c.reuseBuffer = true }
+}
+
+// tupleEmitter fills a tuple from positions and yields it, honouring ReuseBuffer.
+type tupleEmitter[T any] struct {
+	reuse bool
+	buf   []T
+}
+
+func newTupleEmitter[T any](size int, opts []CombinatoricsOption) *tupleEmitter[T] {
+	var cfg combinatoricsConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &tupleEmitter[T]{reuse: cfg.reuseBuffer, buf: make([]T, size)}
+}
+
+// emit yields the tuple whose i-th element is pick(i). It returns false when
+// the consumer stopped iterating.
+func (e *tupleEmitter[T]) emit(yield func([]T) bool, pick func(i int) T) bool {
+	out := e.buf
+	if !e.reuse {
+		out = make([]T, len(e.buf))
+	}
+	for i := range out {
+		out[i] = pick(i)
+	}
+	return yield(out)
+}
+
+// CartesianProduct lazily generates every tuple that takes one element from
+// each of the given sets, such as every combination of config options in a
+// test matrix.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the sets.
+//
+// Parameters:
+//
+//	sets: The sets to combine, in tuple order.
+//	opts: Options such as ReuseBuffer.
+//
+// Returns:
+//
+//	iter.Seq[[]T]: Tuples of len(sets) elements, with the last set varying
+//	               fastest (odometer order). Yields one empty tuple if sets is
+//	               empty, and nothing if any set is empty.
+func CartesianProduct[T any](sets [][]T, opts ...CombinatoricsOption) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for _, s := range sets {
+			if len(s) == 0 {
+				return
+			}
+		}
+		e := newTupleEmitter[T](len(sets), opts)
+		counters := make([]int, len(sets))
+		pick := func(i int) T { return sets[i][counters[i]] }
+		for {
+			if !e.emit(yield, pick) {
+				return
+			}
+			// Advance the odometer from the rightmost position.
+			i := len(sets) - 1
+			for ; i >= 0; i-- {
+				counters[i]++
+				if counters[i] < len(sets[i]) {
+					break
+				}
+				counters[i] = 0
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
+
+// Permutations lazily generates every ordering of the elements of a slice.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	input: The elements to permute. It is not modified.
+//	opts:  Options such as ReuseBuffer.
+//
+// Returns:
+//
+//	iter.Seq[[]T]: All len(input)! orderings, in lexicographic order of
+//	               element positions (the input order comes first). Yields one
+//	               empty slice for nil or empty input.
+func Permutations[T any](input []T, opts ...CombinatoricsOption) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(input)
+		e := newTupleEmitter[T](n, opts)
+		idx := make([]int, n)
+		for i := range idx {
+			idx[i] = i
+		}
+		pick := func(i int) T { return input[idx[i]] }
+		for {
+			if !e.emit(yield, pick) {
+				return
+			}
+			// Next permutation of idx in lexicographic order.
+			i := n - 2
+			for i >= 0 && idx[i] >= idx[i+1] {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			j := n - 1
+			for idx[j] <= idx[i] {
+				j--
+			}
+			idx[i], idx[j] = idx[j], idx[i]
+			Reverse(idx[i+1:])
+		}
+	}
+}
+
+// Combinations lazily generates every way of choosing k elements from a slice,
+// ignoring order and without repetition.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	input: The elements to choose from. It is not modified.
+//	k:     The size of each combination. Must not be negative.
+//	opts:  Options such as ReuseBuffer.
+//
+// Returns:
+//
+//	iter.Seq[[]T]: All C(len(input), k) combinations, each in input order, in
+//	               lexicographic order of positions. Yields one empty slice if
+//	               k is 0, and nothing if k > len(input).
+//
+// Panics if k is negative.
+func Combinations[T any](input []T, k int, opts ...CombinatoricsOption) iter.Seq[[]T] {
+	if k < 0 {
+		panic("functional.Combinations: k must not be negative")
+	}
+	return func(yield func([]T) bool) {
+		n := len(input)
+		if k > n {
+			return
+		}
+		e := newTupleEmitter[T](k, opts)
+		idx := make([]int, k)
+		for i := range idx {
+			idx[i] = i
+		}
+		pick := func(i int) T { return input[idx[i]] }
+		for {
+			if !e.emit(yield, pick) {
+				return
+			}
+			i := k - 1
+			for i >= 0 && idx[i] == n-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			idx[i]++
+			for j := i + 1; j < k; j++ {
+				idx[j] = idx[j-1] + 1
+			}
+		}
+	}
+}
+
+// CombinationsWithReplacement lazily generates every way of choosing k
+// elements from a slice, ignoring order, where an element may be chosen more
+// than once.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	input: The elements to choose from. It is not modified.
+//	k:     The size of each combination. Must not be negative.
+//	opts:  Options such as ReuseBuffer.
+//
+// Returns:
+//
+//	iter.Seq[[]T]: All C(len(input)+k-1, k) multisets, in lexicographic order of
+//	               positions. Yields one empty slice if k is 0, and nothing if
+//	               the input is empty and k > 0.
+//
+// Panics if k is negative.
+func CombinationsWithReplacement[T any](input []T, k int, opts ...CombinatoricsOption) iter.Seq[[]T] {
+	if k < 0 {
+		panic("functional.CombinationsWithReplacement: k must not be negative")
+	}
+	return func(yield func([]T) bool) {
+		n := len(input)
+		if n == 0 && k > 0 {
+			return
+		}
+		e := newTupleEmitter[T](k, opts)
+		idx := make([]int, k)
+		pick := func(i int) T { return input[idx[i]] }
+		for {
+			if !e.emit(yield, pick) {
+				return
+			}
+			i := k - 1
+			for i >= 0 && idx[i] == n-1 {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			idx[i]++
+			for j := i + 1; j < k; j++ {
+				idx[j] = idx[i]
+			}
+		}
+	}
+}
+
+// PowerSet lazily generates every subset of the elements of a slice.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	input: The elements to take subsets of. It is not modified.
+//	opts:  Options such as ReuseBuffer. With ReuseBuffer, subsets of the same
+//	       size share one buffer.
+//
+// Returns:
+//
+//	iter.Seq[[]T]: All 2^len(input) subsets, each in input order, ordered by
+//	               size (starting with the empty set) and then
+//	               lexicographically by position.
+func PowerSet[T any](input []T, opts ...CombinatoricsOption) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for k := 0; k <= len(input); k++ {
+			for subset := range Combinations(input, k, opts...) {
+				if !yield(subset) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// CountCartesianProduct returns the number of tuples CartesianProduct yields
+// for sets of the given sizes: the product of the sizes (1 for no sizes).
+// Panics if any size is negative.
+func CountCartesianProduct(sizes ...int) *big.Int {
+	total := big.NewInt(1)
+	for _, size := range sizes {
+		if size < 0 {
+			panic("functional.CountCartesianProduct: sizes must not be negative")
+		}
+		total.Mul(total, big.NewInt(int64(size)))
+	}
+	return total
+}
+
+// CountPermutations returns the number of orderings Permutations yields for n
+// elements, n!. Panics if n is negative.
+func CountPermutations(n int) *big.Int {
+	if n < 0 {
+		panic("functional.CountPermutations: n must not be negative")
+	}
+	return new(big.Int).MulRange(1, int64(n))
+}
+
+// CountCombinations returns the number of combinations Combinations yields
+// when choosing k of n elements, the binomial coefficient C(n, k), which is 0
+// when k > n. Panics if n or k is negative.
+func CountCombinations(n, k int) *big.Int {
+	if n < 0 || k < 0 {
+		panic("functional.CountCombinations: n and k must not be negative")
+	}
+	if k > n {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Binomial(int64(n), int64(k))
+}
+
+// CountCombinationsWithReplacement returns the number of multisets
+// CombinationsWithReplacement yields when choosing k of n elements,
+// C(n+k-1, k). Panics if n or k is negative.
+func CountCombinationsWithReplacement(n, k int) *big.Int {
+	if n < 0 || k < 0 {
+		panic("functional.CountCombinationsWithReplacement: n and k must not be negative")
+	}
+	if k == 0 {
+		return big.NewInt(1)
+	}
+	if n == 0 {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Binomial(int64(n+k-1), int64(k))
+}
+
+// CountPowerSet returns the number of subsets PowerSet yields for n elements,
+// 2^n. Panics if n is negative.
+func CountPowerSet(n int) *big.Int {
+	if n < 0 {
+		panic("functional.CountPowerSet: n must not be negative")
+	}
+	return new(big.Int).Lsh(big.NewInt(1), uint(n))
+}
@@ -0,0 +1,239 @@
+package functional_test
+
+import (
+	"fmt"
+	"iter"
+	"math/big"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+// collectTuples drains a generator, cloning each tuple so ReuseBuffer
+// generators can be collected too.
+func collectTuples[T any](seq iter.Seq[[]T]) [][]T {
+	result := [][]T{}
+	for tuple := range seq {
+		result = append(result, slices.Clone(tuple))
+	}
+	return result
+}
+
+func TestCartesianProduct(t *testing.T) {
+	got := collectTuples(functional.CartesianProduct([][]string{{"linux", "darwin"}, {"amd64", "arm64"}, {"go1.24"}}))
+	want := [][]string{
+		{"linux", "amd64", "go1.24"},
+		{"linux", "arm64", "go1.24"},
+		{"darwin", "amd64", "go1.24"},
+		{"darwin", "arm64", "go1.24"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CartesianProduct() = %v, want %v", got, want)
+	}
+
+	if got := collectTuples(functional.CartesianProduct([][]int{})); !reflect.DeepEqual(got, [][]int{{}}) {
+		t.Errorf("CartesianProduct() of no sets = %v, want one empty tuple", got)
+	}
+	if got := collectTuples(functional.CartesianProduct([][]int{{1, 2}, {}})); len(got) != 0 {
+		t.Errorf("CartesianProduct() with an empty set = %v, want nothing", got)
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	got := collectTuples(functional.Permutations([]int{1, 2, 3}))
+	want := [][]int{{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Permutations() = %v, want %v", got, want)
+	}
+
+	// Positions, not values, are permuted.
+	if got := collectTuples(functional.Permutations([]string{"x", "x"})); len(got) != 2 {
+		t.Errorf("Permutations() with duplicates = %v, want 2 tuples", got)
+	}
+	if got := collectTuples(functional.Permutations([]int(nil))); !reflect.DeepEqual(got, [][]int{{}}) {
+		t.Errorf("Permutations(nil) = %v, want one empty tuple", got)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	testCases := []struct {
+		name string
+		k    int
+		want [][]string
+	}{
+		{"K2", 2, [][]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"}}},
+		{"K0", 0, [][]string{{}}},
+		{"KEqualsN", 4, [][]string{{"a", "b", "c", "d"}}},
+		{"KAboveN", 5, [][]string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := collectTuples(functional.Combinations([]string{"a", "b", "c", "d"}, tc.k))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Combinations(k=%d) = %v, want %v", tc.k, got, tc.want)
+			}
+		})
+	}
+
+	t.Run("NegativeKPanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Combinations() with negative k did not panic")
+			}
+		}()
+		functional.Combinations([]int{1}, -1)
+	})
+}
+
+func TestCombinationsWithReplacement(t *testing.T) {
+	got := collectTuples(functional.CombinationsWithReplacement([]int{1, 2, 3}, 2))
+	want := [][]int{{1, 1}, {1, 2}, {1, 3}, {2, 2}, {2, 3}, {3, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CombinationsWithReplacement() = %v, want %v", got, want)
+	}
+
+	if got := collectTuples(functional.CombinationsWithReplacement([]int{}, 2)); len(got) != 0 {
+		t.Errorf("CombinationsWithReplacement(empty, 2) = %v, want nothing", got)
+	}
+	if got := collectTuples(functional.CombinationsWithReplacement([]int{}, 0)); !reflect.DeepEqual(got, [][]int{{}}) {
+		t.Errorf("CombinationsWithReplacement(empty, 0) = %v, want one empty tuple", got)
+	}
+}
+
+func TestPowerSet(t *testing.T) {
+	got := collectTuples(functional.PowerSet([]int{1, 2, 3}))
+	want := [][]int{{}, {1}, {2}, {3}, {1, 2}, {1, 3}, {2, 3}, {1, 2, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PowerSet() = %v, want %v", got, want)
+	}
+}
+
+func TestCombinatorics_CountsMatchGenerators(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	count := func(seq iter.Seq[[]int]) int64 {
+		n := int64(0)
+		for range seq {
+			n++
+		}
+		return n
+	}
+
+	checks := []struct {
+		name  string
+		seq   iter.Seq[[]int]
+		count *big.Int
+	}{
+		{"CartesianProduct", functional.CartesianProduct([][]int{input, input[:2], input[:3]}), functional.CountCartesianProduct(5, 2, 3)},
+		{"Permutations", functional.Permutations(input), functional.CountPermutations(5)},
+		{"Combinations", functional.Combinations(input, 3), functional.CountCombinations(5, 3)},
+		{"CombinationsWithReplacement", functional.CombinationsWithReplacement(input, 3), functional.CountCombinationsWithReplacement(5, 3)},
+		{"PowerSet", functional.PowerSet(input), functional.CountPowerSet(5)},
+	}
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			if got := count(c.seq); !c.count.IsInt64() || got != c.count.Int64() {
+				t.Errorf("%s yielded %d tuples, Count* says %v", c.name, got, c.count)
+			}
+		})
+	}
+}
+
+func TestCombinatorics_Counts(t *testing.T) {
+	testCases := []struct {
+		name string
+		got  *big.Int
+		want string
+	}{
+		{"Permutations30", functional.CountPermutations(30), "265252859812191058636308480000000"},
+		{"Permutations0", functional.CountPermutations(0), "1"},
+		{"Combinations100_50", functional.CountCombinations(100, 50), "100891344545564193334812497256"},
+		{"CombinationsKAboveN", functional.CountCombinations(3, 4), "0"},
+		{"WithReplacementEmpty", functional.CountCombinationsWithReplacement(0, 2), "0"},
+		{"WithReplacementK0", functional.CountCombinationsWithReplacement(0, 0), "1"},
+		{"PowerSet100", functional.CountPowerSet(100), "1267650600228229401496703205376"},
+		{"CartesianNone", functional.CountCartesianProduct(), "1"},
+		{"CartesianWithEmpty", functional.CountCartesianProduct(3, 0), "0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got.String() != tc.want {
+				t.Errorf("%s = %v, want %s", tc.name, tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCombinatorics_ReuseBufferAndEarlyStop(t *testing.T) {
+	var first []int
+	n := 0
+	for tuple := range functional.Permutations([]int{1, 2, 3, 4}, functional.ReuseBuffer()) {
+		if first == nil {
+			first = tuple
+		} else if &tuple[0] != &first[0] {
+			t.Fatal("ReuseBuffer() should yield the same backing array every time")
+		}
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("iteration ran %d times after break at 3", n)
+	}
+
+	var a, b []int
+	for tuple := range functional.Combinations([]int{1, 2, 3}, 2) {
+		if a == nil {
+			a = tuple
+		} else {
+			b = tuple
+			break
+		}
+	}
+	if !reflect.DeepEqual(a, []int{1, 2}) || !reflect.DeepEqual(b, []int{1, 3}) {
+		t.Errorf("without ReuseBuffer, earlier tuples must stay intact: %v %v", a, b)
+	}
+
+	stopped := 0
+	for range functional.PowerSet([]int{1, 2, 3}) {
+		stopped++
+		if stopped == 5 {
+			break
+		}
+	}
+	if stopped != 5 {
+		t.Errorf("PowerSet() ignored break, ran %d times", stopped)
+	}
+}
+
+func ExampleCartesianProduct() {
+	matrix := [][]string{{"postgres", "mysql"}, {"tls", "plain"}}
+	fmt.Println("cases:", functional.CountCartesianProduct(len(matrix[0]), len(matrix[1])))
+	for tuple := range functional.CartesianProduct(matrix) {
+		fmt.Println(tuple)
+	}
+	// Output:
+	// cases: 4
+	// [postgres tls]
+	// [postgres plain]
+	// [mysql tls]
+	// [mysql plain]
+}
+
+func BenchmarkPermutations_N8_Alloc(b *testing.B) {
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	for i := 0; i < b.N; i++ {
+		for range functional.Permutations(input) {
+		}
+	}
+}
+
+func BenchmarkPermutations_N8_ReuseBuffer(b *testing.B) {
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	for i := 0; i < b.N; i++ {
+		for range functional.Permutations(input, functional.ReuseBuffer()) {
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package functional
+
+// The combinators below build new functions out of existing ones. Predicates
+// are plain func(T) bool and mappers plain func(A) B, so every result can be
+// passed straight to Filter, Any, All, Find, Partition or Map.
+
+// And returns a predicate that reports whether v satisfies every one of the
+// given predicates. Evaluation short-circuits at the first false.
+//
+// Type Parameters:
+//
+//	T: The type of the value being tested.
+//
+// Parameters:
+//
+//	preds: The predicates to combine. With no predicates, the result
+//	       always returns true.
+//
+// Returns:
+//
+//	func(T) bool: The conjunction of preds.
+func And[T any](preds ...func(T) bool) func(T) bool {
+	return func(v T) bool {
+		for _, p := range preds {
+			if !p(v) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate that reports whether v satisfies at least one of the
+// given predicates. Evaluation short-circuits at the first true.
+//
+// Type Parameters:
+//
+//	T: The type of the value being tested.
+//
+// Parameters:
+//
+//	preds: The predicates to combine. With no predicates, the result
+//	       always returns false.
+//
+// Returns:
+//
+//	func(T) bool: The disjunction of preds.
+func Or[T any](preds ...func(T) bool) func(T) bool {
+	return func(v T) bool {
+		for _, p := range preds {
+			if p(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a predicate that negates p.
+//
+// Type Parameters:
+//
+//	T: The type of the value being tested.
+//
+// Parameters:
+//
+//	p: The predicate to negate.
+//
+// Returns:
+//
+//	func(T) bool: A predicate returning !p(v).
+func Not[T any](p func(T) bool) func(T) bool {
+	return func(v T) bool {
+		return !p(v)
+	}
+}
+
+// None returns a predicate that reports whether v satisfies none of the given
+// predicates. It is equivalent to Not(Or(preds...)).
+//
+// Type Parameters:
+//
+//	T: The type of the value being tested.
+//
+// Parameters:
+//
+//	preds: The predicates to test. With no predicates, the result always
+//	       returns true.
+//
+// Returns:
+//
+//	func(T) bool: A predicate that is true when every pred is false.
+func None[T any](preds ...func(T) bool) func(T) bool {
+	return Not(Or(preds...))
+}
+
+// Identity returns its argument unchanged. It is useful wherever a mapper or
+// key function is required but no transformation is wanted, such as
+// GroupBy(words, Identity[string]).
+func Identity[T any](v T) T {
+	return v
+}
+
+// Const returns a function that ignores its argument and always returns v.
+//
+// Type Parameters:
+//
+//	A: The type of the ignored argument.
+//	T: The type of the constant value.
+//
+// Parameters:
+//
+//	v: The value to return.
+//
+// Returns:
+//
+//	func(A) T: A function that always returns v.
+func Const[A, T any](v T) func(A) T {
+	return func(A) T {
+		return v
+	}
+}
+
+// Pipe2 returns a function that applies f and then g, left to right, so
+// Pipe2(f, g)(a) == g(f(a)). Go has no variadic type parameters, so Pipe3,
+// Pipe4 and Pipe5 cover longer typed chains; use PipeN when every step has the
+// same input and output type.
+//
+// Type Parameters:
+//
+//	A: The input type of the pipeline.
+//	B: The intermediate type.
+//	C: The output type of the pipeline.
+//
+// Parameters:
+//
+//	f: The first function to apply.
+//	g: The second function to apply.
+//
+// Returns:
+//
+//	func(A) C: The composed function.
+func Pipe2[A, B, C any](f func(A) B, g func(B) C) func(A) C {
+	return func(a A) C {
+		return g(f(a))
+	}
+}
+
+// Pipe3 returns a function that applies f, g and h in order. See Pipe2.
+func Pipe3[A, B, C, D any](f func(A) B, g func(B) C, h func(C) D) func(A) D {
+	return func(a A) D {
+		return h(g(f(a)))
+	}
+}
+
+// Pipe4 returns a function that applies f, g, h and i in order. See Pipe2.
+func Pipe4[A, B, C, D, E any](f func(A) B, g func(B) C, h func(C) D, i func(D) E) func(A) E {
+	return func(a A) E {
+		return i(h(g(f(a))))
+	}
+}
+
+// Pipe5 returns a function that applies f, g, h, i and j in order. See Pipe2.
+func Pipe5[A, B, C, D, E, F any](f func(A) B, g func(B) C, h func(C) D, i func(D) E, j func(E) F) func(A) F {
+	return func(a A) F {
+		return j(i(h(g(f(a)))))
+	}
+}
+
+// PipeN returns a function that applies any number of same-typed functions
+// left to right. With no functions, it returns Identity.
+//
+// Type Parameters:
+//
+//	T: The input and output type of every step.
+//
+// Parameters:
+//
+//	fns: The functions to apply, in order. The slice is copied, so later
+//	     changes to the caller's slice do not affect the result.
+//
+// Returns:
+//
+//	func(T) T: The composed function.
+func PipeN[T any](fns ...func(T) T) func(T) T {
+	steps := append([]func(T) T(nil), fns...)
+	return func(v T) T {
+		for _, fn := range steps {
+			v = fn(v)
+		}
+		return v
+	}
+}
+
+// Compose2 returns the mathematical composition f ∘ g, which applies g first:
+// Compose2(f, g)(a) == f(g(a)). It is Pipe2 with the arguments reversed.
+//
+// Type Parameters:
+//
+//	A: The input type of g.
+//	B: The output type of g and input type of f.
+//	C: The output type of f.
+//
+// Parameters:
+//
+//	f: The outer function, applied last.
+//	g: The inner function, applied first.
+//
+// Returns:
+//
+//	func(A) C: The composed function.
+func Compose2[A, B, C any](f func(B) C, g func(A) B) func(A) C {
+	return Pipe2(g, f)
+}
+
+// Compose3 returns f ∘ g ∘ h, applying h first. See Compose2.
+func Compose3[A, B, C, D any](f func(C) D, g func(B) C, h func(A) B) func(A) D {
+	return Pipe3(h, g, f)
+}
+
+// Compose4 returns f ∘ g ∘ h ∘ i, applying i first. See Compose2.
+func Compose4[A, B, C, D, E any](f func(D) E, g func(C) D, h func(B) C, i func(A) B) func(A) E {
+	return Pipe4(i, h, g, f)
+}
+
+// Compose5 returns f ∘ g ∘ h ∘ i ∘ j, applying j first. See Compose2.
+func Compose5[A, B, C, D, E, F any](f func(E) F, g func(D) E, h func(C) D, i func(B) C, j func(A) B) func(A) F {
+	return Pipe5(j, i, h, g, f)
+}
+
+// ComposeN returns the composition of same-typed functions, applied right to
+// left. With no functions, it returns Identity.
+func ComposeN[T any](fns ...func(T) T) func(T) T {
+	steps := make([]func(T) T, len(fns))
+	for i, fn := range fns {
+		steps[len(fns)-1-i] = fn
+	}
+	return PipeN(steps...)
+}
+
+// Partial binds the first argument of a two-argument function, producing a
+// one-argument function suitable for Map or Filter.
+//
+// Type Parameters:
+//
+//	A: The type of the bound first argument.
+//	B: The type of the remaining argument.
+//	R: The result type.
+//
+// Parameters:
+//
+//	fn: The function to bind.
+//	a: The value for fn's first argument.
+//
+// Returns:
+//
+//	func(B) R: A function returning fn(a, b).
+func Partial[A, B, R any](fn func(A, B) R, a A) func(B) R {
+	return func(b B) R {
+		return fn(a, b)
+	}
+}
+
+// PartialRight binds the second argument of a two-argument function. It fits
+// the common case where the slice element is the first argument, as in
+// Map(names, PartialRight(strings.TrimPrefix, "tmp_")).
+//
+// Type Parameters:
+//
+//	A: The type of the remaining first argument.
+//	B: The type of the bound second argument.
+//	R: The result type.
+//
+// Parameters:
+//
+//	fn: The function to bind.
+//	b: The value for fn's second argument.
+//
+// Returns:
+//
+//	func(A) R: A function returning fn(a, b).
+func PartialRight[A, B, R any](fn func(A, B) R, b B) func(A) R {
+	return func(a A) R {
+		return fn(a, b)
+	}
+}
+
+// Partial3 binds the first argument of a three-argument function. See Partial.
+func Partial3[A, B, C, R any](fn func(A, B, C) R, a A) func(B, C) R {
+	return func(b B, c C) R {
+		return fn(a, b, c)
+	}
+}
+
+// Curry converts a two-argument function into a chain of one-argument
+// functions, so Curry(fn)(a)(b) == fn(a, b).
+//
+// Type Parameters:
+//
+//	A: The type of the first argument.
+//	B: The type of the second argument.
+//	R: The result type.
+//
+// Parameters:
+//
+//	fn: The function to curry.
+//
+// Returns:
+//
+//	func(A) func(B) R: The curried form of fn.
+func Curry[A, B, R any](fn func(A, B) R) func(A) func(B) R {
+	return func(a A) func(B) R {
+		return Partial(fn, a)
+	}
+}
+
+// Curry3 converts a three-argument function into a chain of one-argument
+// functions, so Curry3(fn)(a)(b)(c) == fn(a, b, c).
+func Curry3[A, B, C, R any](fn func(A, B, C) R) func(A) func(B) func(C) R {
+	return func(a A) func(B) func(C) R {
+		return func(b B) func(C) R {
+			return func(c C) R {
+				return fn(a, b, c)
+			}
+		}
+	}
+}
+
+// Uncurry converts a curried function back into a two-argument function.
+func Uncurry[A, B, R any](fn func(A) func(B) R) func(A, B) R {
+	return func(a A, b B) R {
+		return fn(a)(b)
+	}
+}
@@ -0,0 +1,158 @@
+package functional_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+func TestPredicateAlgebra(t *testing.T) {
+	positive := func(n int) bool { return n > 0 }
+	even := func(n int) bool { return n%2 == 0 }
+	input := []int{-4, -3, 0, 1, 2, 7, 8}
+
+	testCases := []struct {
+		name string
+		pred func(int) bool
+		want []int
+	}{
+		{"And", functional.And(positive, even), []int{2, 8}},
+		{"Or", functional.Or(positive, even), []int{-4, 0, 1, 2, 7, 8}},
+		{"Not", functional.Not(positive), []int{-4, -3, 0}},
+		{"None", functional.None(positive, even), []int{-3}},
+		{"AndEmpty", functional.And[int](), input},
+		{"OrEmpty", functional.Or[int](), []int{}},
+		{"NoneEmpty", functional.None[int](), input},
+		{"Nested", functional.Or(functional.And(positive, functional.Not(even)), functional.Const[int](false)), []int{1, 7}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := functional.Filter(input, tc.pred)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Filter() with %s = %v, want %v", tc.name, got, tc.want)
+			}
+		})
+	}
+
+	t.Run("ShortCircuit", func(t *testing.T) {
+		calls := 0
+		counted := func(int) bool { calls++; return true }
+		functional.Or(positive, counted)(5)
+		functional.And(even, counted)(5)
+		if calls != 0 {
+			t.Errorf("later predicates called %d times, want 0", calls)
+		}
+	})
+}
+
+func TestPipeAndCompose(t *testing.T) {
+	double := func(n int) int { return n * 2 }
+	inc := func(n int) int { return n + 1 }
+
+	if got := functional.Pipe2(double, inc)(5); got != 11 {
+		t.Errorf("Pipe2(double, inc)(5) = %d, want 11", got)
+	}
+	if got := functional.Compose2(double, inc)(5); got != 12 {
+		t.Errorf("Compose2(double, inc)(5) = %d, want 12", got)
+	}
+
+	// Typed chains change type at each step.
+	describe := functional.Pipe5(
+		strings.TrimSpace,
+		strconv.Quote,
+		func(s string) int { return len(s) },
+		double,
+		strconv.Itoa,
+	)
+	if got := describe("  go  "); got != "8" {
+		t.Errorf("Pipe5(...) = %q, want %q", got, "8")
+	}
+	if got := functional.Compose3(strconv.Itoa, inc, double)(5); got != "11" {
+		t.Errorf("Compose3(...) = %q, want %q", got, "11")
+	}
+	if got := functional.Pipe3(double, inc, double)(1); got != 6 {
+		t.Errorf("Pipe3(...) = %d, want 6", got)
+	}
+	if got := functional.Pipe4(double, inc, double, inc)(1); got != 7 {
+		t.Errorf("Pipe4(...) = %d, want 7", got)
+	}
+	if got := functional.Compose4(inc, double, inc, double)(1); got != 7 {
+		t.Errorf("Compose4(...) = %d, want 7", got)
+	}
+	if got := functional.Compose5(inc, double, inc, double, inc)(1); got != 11 {
+		t.Errorf("Compose5(...) = %d, want 11", got)
+	}
+
+	fns := []func(int) int{double, inc, double}
+	pipe := functional.PipeN(fns...)
+	compose := functional.ComposeN(fns...)
+	fns[0] = inc // must not affect the composed functions
+	if got := pipe(1); got != 6 {
+		t.Errorf("PipeN(...)(1) = %d, want 6", got)
+	}
+	if got := compose(1); got != 6 {
+		t.Errorf("ComposeN(...)(1) = %d, want 6", got)
+	}
+	if got := functional.PipeN[int]()(9); got != 9 {
+		t.Errorf("PipeN()(9) = %d, want 9", got)
+	}
+}
+
+func TestPartialAndCurry(t *testing.T) {
+	if got := functional.Map([]string{"tmp_a", "b"}, functional.PartialRight(strings.TrimPrefix, "tmp_")); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Map with PartialRight = %v", got)
+	}
+	hasGo := functional.Partial(strings.Contains, "golang")
+	if !hasGo("lang") || hasGo("rust") {
+		t.Error("Partial(strings.Contains, \"golang\") gave wrong results")
+	}
+	replaceA := functional.Partial3(strings.ReplaceAll, "banana")
+	if got := replaceA("a", "o"); got != "bonono" {
+		t.Errorf("Partial3(...) = %q, want %q", got, "bonono")
+	}
+
+	join := func(sep, a string) string { return a + sep }
+	curried := functional.Curry(join)
+	if got := curried("!")("hi"); got != "hi!" {
+		t.Errorf("Curry(...) = %q, want %q", got, "hi!")
+	}
+	if got := functional.Uncurry(curried)("?", "ok"); got != "ok?" {
+		t.Errorf("Uncurry(Curry(...)) = %q, want %q", got, "ok?")
+	}
+	if got := functional.Curry3(strings.ReplaceAll)("aaa")("a")("b"); got != "bbb" {
+		t.Errorf("Curry3(strings.ReplaceAll) = %q, want %q", got, "bbb")
+	}
+}
+
+func TestIdentityAndConst(t *testing.T) {
+	groups := functional.GroupBy([]string{"a", "b", "a"}, functional.Identity[string])
+	if len(groups["a"]) != 2 || len(groups["b"]) != 1 {
+		t.Errorf("GroupBy with Identity = %v", groups)
+	}
+	if got := functional.Map([]int{1, 2, 3}, functional.Const[int]("x")); !reflect.DeepEqual(got, []string{"x", "x", "x"}) {
+		t.Errorf("Map with Const = %v", got)
+	}
+}
+
+func ExamplePipe2() {
+	type user struct {
+		Name   string
+		Active bool
+		Age    int
+	}
+	users := []user{{"ada", true, 36}, {"bob", false, 41}, {"cy", true, 17}}
+
+	adult := func(u user) bool { return u.Age >= 18 }
+	active := func(u user) bool { return u.Active }
+	label := functional.Pipe2(func(u user) string { return u.Name }, strings.ToUpper)
+
+	fmt.Println(functional.Map(functional.Filter(users, functional.And(adult, active)), label))
+	fmt.Println(functional.Map(functional.Filter(users, functional.Not(active)), label))
+	// Output:
+	// [ADA]
+	// [BOB]
+}
@@ -0,0 +1,123 @@
+package functional
+
+import (
+	"cmp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Comparator orders two values of type T. It returns a negative number if a
+// sorts before b, a positive number if a sorts after b, and zero if they are
+// equivalent. Its signature matches the comparison functions accepted by the
+// standard slices package, so a Comparator can be passed to slices.SortFunc
+// directly.
+type Comparator[T any] func(a, b T) int
+
+// Comparing returns a Comparator that orders values by an ordered key.
+//
+// Type Parameters:
+//
+//	T: The type of the values being compared.
+//	K: The type of the extracted key. Must be ordered.
+//
+// Parameters:
+//
+//	key: A function that extracts the sort key from a value. It is called
+//	     twice per comparison, so it should be cheap.
+//
+// Returns:
+//
+//	Comparator[T]: Orders values by cmp.Compare on their keys. Floating-point
+//	               NaN keys sort before all other keys.
+func Comparing[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// ComparingFunc returns a Comparator that orders values by a key of any type,
+// compared with the given function. It is the hook for keys that are not
+// cmp.Ordered, and for collation-aware string ordering:
+//
+//	byName := ComparingFunc(func(p Person) string { return p.Name }, collator.CompareString)
+//
+// where collator is, for example, a *collate.Collator from
+// golang.org/x/text/collate.
+func ComparingFunc[T, K any](key func(T) K, compare func(a, b K) int) Comparator[T] {
+	return func(a, b T) int {
+		return compare(key(a), key(b))
+	}
+}
+
+// ThenComparing returns a Comparator that orders by c first and falls back to
+// next when c considers two values equivalent.
+func (c Comparator[T]) ThenComparing(next Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if result := c(a, b); result != 0 {
+			return result
+		}
+		return next(a, b)
+	}
+}
+
+// Reversed returns a Comparator that imposes the opposite order to c.
+func (c Comparator[T]) Reversed() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// NilsFirst adapts a Comparator for values to one for pointers, ordering nil
+// pointers before all non-nil pointers. Non-nil pointers are compared by the
+// values they point to.
+func NilsFirst[T any](c Comparator[T]) Comparator[*T] {
+	return nilsComparator(c, -1)
+}
+
+// NilsLast adapts a Comparator for values to one for pointers, ordering nil
+// pointers after all non-nil pointers. Non-nil pointers are compared by the
+// values they point to.
+func NilsLast[T any](c Comparator[T]) Comparator[*T] {
+	return nilsComparator(c, 1)
+}
+
+// nilsComparator implements NilsFirst and NilsLast; nilOrder is the result
+// for a nil a compared to a non-nil b.
+func nilsComparator[T any](c Comparator[T], nilOrder int) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return nilOrder
+		case b == nil:
+			return -nilOrder
+		default:
+			return c(*a, *b)
+		}
+	}
+}
+
+// CompareFold compares two strings under Unicode case folding, so "apple"
+// and "Apple" are equivalent. It can be passed to ComparingFunc as a simple,
+// allocation-free collation hook. It does not handle locale-specific rules;
+// use a collator from golang.org/x/text/collate for that.
+func CompareFold(a, b string) int {
+	for a != "" && b != "" {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			if result := cmp.Compare(foldRune(ra), foldRune(rb)); result != 0 {
+				return result
+			}
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// foldRune maps a rune to a canonical case so that runes equal under simple
+// case folding map to the same value.
+func foldRune(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
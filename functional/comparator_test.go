@@ -0,0 +1,144 @@
+package functional_test
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+type employee struct {
+	Name    string
+	Dept    string
+	Salary  int
+	Manager *string
+}
+
+var employees = []employee{
+	{Name: "dana", Dept: "ops", Salary: 70},
+	{Name: "Alex", Dept: "eng", Salary: 90},
+	{Name: "carl", Dept: "eng", Salary: 90},
+	{Name: "Bea", Dept: "ops", Salary: 85},
+}
+
+func employeeNames(es []employee) []string {
+	return functional.Map(es, func(e employee) string { return e.Name })
+}
+
+func TestComparing(t *testing.T) {
+	bySalary := functional.Comparing(func(e employee) int { return e.Salary })
+	if bySalary(employees[0], employees[1]) >= 0 {
+		t.Error("Comparing() should order 70 before 90")
+	}
+	if bySalary(employees[1], employees[2]) != 0 {
+		t.Error("Comparing() should treat equal keys as equivalent")
+	}
+	if bySalary.Reversed()(employees[0], employees[1]) <= 0 {
+		t.Error("Reversed() should order 90 before 70")
+	}
+}
+
+func TestComparator_ThenComparing(t *testing.T) {
+	byDeptThenSalaryDescThenName := functional.Comparing(func(e employee) string { return e.Dept }).
+		ThenComparing(functional.Comparing(func(e employee) int { return e.Salary }).Reversed()).
+		ThenComparing(functional.Comparing(func(e employee) string { return e.Name }))
+
+	got := slices.Clone(employees)
+	slices.SortFunc(got, byDeptThenSalaryDescThenName)
+
+	want := []string{"Alex", "carl", "Bea", "dana"}
+	if names := employeeNames(got); !reflect.DeepEqual(names, want) {
+		t.Errorf("sorted names = %v, want %v", names, want)
+	}
+}
+
+func TestComparingFunc_Collation(t *testing.T) {
+	byNameFold := functional.ComparingFunc(func(e employee) string { return e.Name }, functional.CompareFold)
+
+	got := slices.Clone(employees)
+	slices.SortFunc(got, byNameFold)
+
+	want := []string{"Alex", "Bea", "carl", "dana"}
+	if names := employeeNames(got); !reflect.DeepEqual(names, want) {
+		t.Errorf("case-insensitive sort = %v, want %v", names, want)
+	}
+}
+
+func TestCompareFold(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"apple", "APPLE", 0},
+		{"Straße", "STRASSE", 1}, // Simple folding does not expand ß.
+		{"Éclair", "éclair", 0},
+		{"abc", "ABD", -1},
+		{"ab", "AB c", -1},
+		{"b", "A", 1},
+		{"", "", 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			if got := functional.CompareFold(tc.a, tc.b); got != tc.want {
+				t.Errorf("CompareFold(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNilsFirstAndNilsLast(t *testing.T) {
+	one, two := 1, 2
+	input := []*int{&two, nil, &one, nil}
+
+	first := slices.Clone(input)
+	slices.SortFunc(first, functional.NilsFirst(functional.Comparator[int](func(a, b int) int { return a - b })))
+	if first[0] != nil || first[1] != nil || *first[2] != 1 || *first[3] != 2 {
+		t.Errorf("NilsFirst() order = %v", derefAll(first))
+	}
+
+	last := slices.Clone(input)
+	slices.SortFunc(last, functional.NilsLast(functional.Comparing(func(n int) int { return n })))
+	if *last[0] != 1 || *last[1] != 2 || last[2] != nil || last[3] != nil {
+		t.Errorf("NilsLast() order = %v", derefAll(last))
+	}
+}
+
+func TestNilsLast_PointerField(t *testing.T) {
+	boss := "bea"
+	staff := []employee{{Name: "x"}, {Name: "y", Manager: &boss}}
+	byManager := functional.ComparingFunc(func(e employee) *string { return e.Manager },
+		functional.NilsLast(functional.Comparing(func(s string) string { return s })))
+
+	slices.SortFunc(staff, byManager)
+	if staff[0].Name != "y" {
+		t.Errorf("employees with a manager should sort first, got %v", employeeNames(staff))
+	}
+}
+
+func derefAll(ptrs []*int) []any {
+	out := make([]any, len(ptrs))
+	for i, p := range ptrs {
+		if p != nil {
+			out[i] = *p
+		}
+	}
+	return out
+}
+
+func ExampleComparator_ThenComparing() {
+	type file struct {
+		Dir  string
+		Size int
+	}
+	files := []file{{"/b", 10}, {"/a", 5}, {"/b", 30}, {"/a", 7}}
+
+	byDirThenLargest := functional.Comparing(func(f file) string { return f.Dir }).
+		ThenComparing(functional.Comparing(func(f file) int { return f.Size }).Reversed())
+	slices.SortFunc(files, byDirThenLargest)
+
+	fmt.Println(files)
+	// Output:
+	// [{/a 7} {/a 5} {/b 30} {/b 10}]
+}
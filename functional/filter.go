@@ -35,3 +35,34 @@ func Filter[T any](input []T, predicate func(T) bool) []T {
 
 	return result
 }
+
+// Partition splits a slice into the elements that satisfy the predicate and
+// the elements that do not, in a single pass.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//
+// Parameters:
+//
+//	input: The slice to split. Can be nil or empty.
+//	predicate: The function that decides which side an element goes to.
+//
+// Returns:
+//
+//	matched: The elements for which predicate returned true.
+//	rest:    The elements for which predicate returned false.
+//	Both are empty non-nil slices if input is nil or empty, and both preserve
+//	the relative order of the input.
+func Partition[T any](input []T, predicate func(T) bool) (matched, rest []T) {
+	matched = make([]T, 0)
+	rest = make([]T, 0)
+	for _, v := range input {
+		if predicate(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
@@ -154,6 +154,45 @@ func ExampleFilter_strings() {
 	// Output: [apple apricot avocado]
 }
 
+// TestPartition checks that Partition splits a slice into both sides in order.
+func TestPartition(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       []int
+		wantMatched []int
+		wantRest    []int
+	}{
+		{"Mixed", []int{1, 2, 3, 4, 5, 6}, []int{2, 4, 6}, []int{1, 3, 5}},
+		{"AllMatch", []int{2, 4}, []int{2, 4}, []int{}},
+		{"NoneMatch", []int{1, 3}, []int{}, []int{1, 3}},
+		{"Empty", []int{}, []int{}, []int{}},
+		{"Nil", nil, []int{}, []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matched, rest := functional.Partition(tc.input, isEvenPredicate)
+			if !reflect.DeepEqual(matched, tc.wantMatched) {
+				t.Errorf("Partition() matched = %#v, want %#v", matched, tc.wantMatched)
+			}
+			if !reflect.DeepEqual(rest, tc.wantRest) {
+				t.Errorf("Partition() rest = %#v, want %#v", rest, tc.wantRest)
+			}
+		})
+	}
+}
+
+// ExamplePartition demonstrates splitting scores into passed and failed.
+func ExamplePartition() {
+	scores := []int{72, 45, 90, 38, 61}
+	passed, failed := functional.Partition(scores, func(s int) bool { return s >= 50 })
+	fmt.Println("Passed:", passed)
+	fmt.Println("Failed:", failed)
+	// Output:
+	// Passed: [72 90 61]
+	// Failed: [45 38]
+}
+
 // --- Benchmarks ---
 
 // Re-use helper from map_test.go conceptually - generate a slice of ints
@@ -1,5 +1,12 @@
 package functional
 
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
 // GroupBy takes a slice and a classifier function, returning a map where keys
 // are the results of applying the classifier function to each element, and
 // values are slices containing the elements that produced that key.
@@ -47,3 +54,362 @@ func GroupBy[T any, K comparable](input []T, classifier func(element T) K) map[K
 
 	return result
 }
+
+// GroupByMap groups the elements of a slice by a classifier like GroupBy, but
+// stores a projection of each element instead of the element itself.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The type returned by the classifier function (must be comparable).
+//	V: The type stored in each group.
+//
+// Parameters:
+//
+//	input:      The slice to group. Can be nil or empty.
+//	classifier: A function that returns the group key for an element.
+//	valueFunc:  A function that returns the value to store for an element.
+//
+// Returns:
+//
+//	map[K][]V: A new map from group key to the projected values of the
+//	           elements in that group. Returns an empty, non-nil map if the
+//	           input slice is nil or empty.
+//
+// Values within each group keep the relative order of the input slice.
+func GroupByMap[T any, K comparable, V any](
+	input []T, classifier func(element T) K, valueFunc func(element T) V,
+) map[K][]V {
+	result := make(map[K][]V)
+	for _, item := range input {
+		key := classifier(item)
+		result[key] = append(result[key], valueFunc(item))
+	}
+	return result
+}
+
+// GroupByAgg groups the elements of a slice by a classifier and folds each
+// group into a single accumulator, without materializing the group members.
+// It is the grouped counterpart of Reduce.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The type returned by the classifier function (must be comparable).
+//	A: The type of the per-group accumulator.
+//
+// Parameters:
+//
+//	input:      The slice to group. Can be nil or empty.
+//	classifier: A function that returns the group key for an element.
+//	initial:    The starting accumulator value for every new group.
+//	step:       The function that combines a group's accumulator with the
+//	            next element of that group.
+//
+// Returns:
+//
+//	map[K]A: A new map from group key to the folded accumulator. Returns an
+//	         empty, non-nil map if the input slice is nil or empty.
+//
+// Elements are folded in input order. Because initial is copied by value into
+// every group, accumulators that are references (slices, maps, pointers)
+// should be allocated inside step rather than shared through initial.
+func GroupByAgg[T any, K comparable, A any](
+	input []T, classifier func(element T) K, initial A, step func(acc A, element T) A,
+) map[K]A {
+	result := make(map[K]A)
+	for _, item := range input {
+		key := classifier(item)
+		acc, ok := result[key]
+		if !ok {
+			acc = initial
+		}
+		result[key] = step(acc, item)
+	}
+	return result
+}
+
+// CountBy counts how many elements of a slice fall into each group.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The type returned by the classifier function (must be comparable).
+//
+// Parameters:
+//
+//	input:      The slice to count. Can be nil or empty.
+//	classifier: A function that returns the group key for an element.
+//
+// Returns:
+//
+//	map[K]int: A new map from group key to the number of elements in that
+//	           group. Returns an empty, non-nil map if the input is nil or empty.
+func CountBy[T any, K comparable](input []T, classifier func(element T) K) map[K]int {
+	result := make(map[K]int)
+	for _, item := range input {
+		result[classifier(item)]++
+	}
+	return result
+}
+
+// SumBy sums a numeric projection of the elements of a slice per group.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The type returned by the classifier function (must be comparable).
+//	N: The numeric type being summed.
+//
+// Parameters:
+//
+//	input:      The slice to sum. Can be nil or empty.
+//	classifier: A function that returns the group key for an element.
+//	valueFunc:  A function that returns the number to add for an element.
+//
+// Returns:
+//
+//	map[K]N: A new map from group key to the sum of its values. Returns an
+//	         empty, non-nil map if the input slice is nil or empty.
+//
+// Sums use ordinary Go arithmetic: integer sums wrap on overflow and floating
+// point sums propagate NaN and infinities.
+func SumBy[T any, K comparable, N Number](
+	input []T, classifier func(element T) K, valueFunc func(element T) N,
+) map[K]N {
+	result := make(map[K]N)
+	for _, item := range input {
+		result[classifier(item)] += valueFunc(item)
+	}
+	return result
+}
+
+// Group is a single group produced by the ordered GroupBy variants.
+type Group[K comparable, T any] struct {
+	Key   K
+	Items []T
+}
+
+// GroupByOrdered groups the elements of a slice like GroupBy but returns the
+// groups as a slice sorted by key, which gives a deterministic iteration order.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The type returned by the classifier function. Must be ordered.
+//
+// Parameters:
+//
+//	input:      The slice to group. Can be nil or empty.
+//	classifier: A function that returns the group key for an element.
+//
+// Returns:
+//
+//	[]Group[K, T]: The groups in ascending key order. Items within a group keep
+//	               their input order. Returns an empty slice if the input is
+//	               nil or empty.
+func GroupByOrdered[T any, K cmp.Ordered](input []T, classifier func(element T) K) []Group[K, T] {
+	groups := GroupBy(input, classifier)
+	result := make([]Group[K, T], 0, len(groups))
+	for _, key := range SortedKeys(groups) {
+		result = append(result, Group[K, T]{Key: key, Items: groups[key]})
+	}
+	return result
+}
+
+// CollisionPolicy decides what KeyBy does when two elements produce the same key.
+type CollisionPolicy int
+
+const (
+	// KeepFirst keeps the first element seen for a key and ignores the rest.
+	KeepFirst CollisionPolicy = iota
+	// KeepLast overwrites earlier elements so the last one seen for a key wins.
+	KeepLast
+	// ErrorOnCollision stops at the first duplicate key and returns a
+	// *DuplicateKeyError.
+	ErrorOnCollision
+)
+
+// ErrDuplicateKey is the sentinel wrapped by every DuplicateKeyError, so
+// callers can test for duplicates with errors.Is without naming the key type.
+var ErrDuplicateKey = errors.New("functional: duplicate key")
+
+// DuplicateKeyError reports a key that was produced by more than one element
+// where keys are required to be unique.
+type DuplicateKeyError[K comparable] struct {
+	Key        K   // The duplicated key.
+	FirstIndex int // Index of the first element that produced Key.
+	Index      int // Index of the element that produced Key again.
+}
+
+// Error implements the error interface.
+func (e *DuplicateKeyError[K]) Error() string {
+	return fmt.Sprintf("functional: duplicate key %v at index %d (first seen at index %d)",
+		e.Key, e.Index, e.FirstIndex)
+}
+
+// Unwrap returns ErrDuplicateKey.
+func (e *DuplicateKeyError[K]) Unwrap() error {
+	return ErrDuplicateKey
+}
+
+// KeyBy indexes the elements of a slice by a key that is expected to be unique.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The type returned by the key function (must be comparable).
+//
+// Parameters:
+//
+//	input:   The slice to index. Can be nil or empty.
+//	keyFunc: A function that returns the key for an element.
+//	policy:  What to do when two elements share a key (KeepFirst, KeepLast
+//	         or ErrorOnCollision).
+//
+// Returns:
+//
+//	map[K]T: A new map from key to element. Returns an empty, non-nil map if
+//	         the input slice is nil or empty.
+//	error:   With ErrorOnCollision, a *DuplicateKeyError[K] for the first
+//	         duplicate found, in which case the map holds the elements indexed
+//	         before it (fail-fast, like MapErr). Always nil for other policies.
+//
+// Panics if policy is not one of the defined CollisionPolicy values.
+func KeyBy[T any, K comparable](input []T, keyFunc func(element T) K, policy CollisionPolicy) (map[K]T, error) {
+	if policy < KeepFirst || policy > ErrorOnCollision {
+		panic("functional.KeyBy: unknown collision policy")
+	}
+
+	result := make(map[K]T, len(input))
+	// Only track first positions when they are needed for error reporting.
+	var firstIndex map[K]int
+	if policy == ErrorOnCollision {
+		firstIndex = make(map[K]int, len(input))
+	}
+
+	for i, item := range input {
+		key := keyFunc(item)
+		if _, exists := result[key]; exists {
+			switch policy {
+			case KeepFirst:
+				continue
+			case ErrorOnCollision:
+				return result, &DuplicateKeyError[K]{Key: key, FirstIndex: firstIndex[key], Index: i}
+			}
+		} else if firstIndex != nil {
+			firstIndex[key] = i
+		}
+		result[key] = item
+	}
+	return result, nil
+}
+
+// GroupBy2 groups the elements of a slice on two levels: first by outer, then
+// within each outer group by inner. It replaces nested loops over GroupBy
+// output for region→product style breakdowns.
+//
+// Type Parameters:
+//
+//	T:  The type of elements in the input slice.
+//	K1: The type of the outer key (must be comparable).
+//	K2: The type of the inner key (must be comparable).
+//
+// Parameters:
+//
+//	input: The slice to group. Can be nil or empty.
+//	outer: A function that returns the first-level key for an element.
+//	inner: A function that returns the second-level key for an element.
+//
+// Returns:
+//
+//	map[K1]map[K2][]T: A new nested map. Every inner map is non-nil and
+//	                   non-empty. Returns an empty, non-nil map if the input
+//	                   slice is nil or empty.
+//
+// Elements within each leaf slice keep their input order.
+func GroupBy2[T any, K1, K2 comparable](
+	input []T, outer func(element T) K1, inner func(element T) K2,
+) map[K1]map[K2][]T {
+	result := make(map[K1]map[K2][]T)
+	for _, item := range input {
+		k1 := outer(item)
+		level, ok := result[k1]
+		if !ok {
+			level = make(map[K2][]T)
+			result[k1] = level
+		}
+		k2 := inner(item)
+		level[k2] = append(level[k2], item)
+	}
+	return result
+}
+
+// CompositeGroup is a single group produced by GroupByN. Keys holds one key
+// per classifier, in classifier order.
+type CompositeGroup[K comparable, T any] struct {
+	Keys  []K
+	Items []T
+}
+
+// groupTrieNode indexes composite keys one level at a time so GroupByN can
+// look up a group without encoding the key tuple into a single map key.
+type groupTrieNode[K comparable] struct {
+	children map[K]*groupTrieNode[K]
+	index    int // Position in the result slice; only meaningful on leaves.
+}
+
+// GroupByN groups the elements of a slice by a composite key made of any
+// number of classifiers that share a key type.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	K: The type returned by every classifier (must be comparable). Use a
+//	   common type such as string or any when the levels differ in type.
+//
+// Parameters:
+//
+//	input:       The slice to group. Can be nil or empty.
+//	classifiers: The functions that make up the composite key, outermost first.
+//
+// Returns:
+//
+//	[]CompositeGroup[K, T]: One entry per distinct key tuple, in order of first
+//	                        appearance in the input. Returns an empty slice if
+//	                        the input is nil or empty.
+//
+// Panics if no classifiers are given.
+func GroupByN[T any, K comparable](input []T, classifiers ...func(element T) K) []CompositeGroup[K, T] {
+	if len(classifiers) == 0 {
+		panic("functional.GroupByN: at least one classifier is required")
+	}
+
+	result := make([]CompositeGroup[K, T], 0)
+	root := &groupTrieNode[K]{children: make(map[K]*groupTrieNode[K])}
+	keys := make([]K, len(classifiers)) // Scratch buffer reused for every element.
+
+	for _, item := range input {
+		node := root
+		for level, classifier := range classifiers {
+			key := classifier(item)
+			keys[level] = key
+			child, ok := node.children[key]
+			if !ok {
+				child = &groupTrieNode[K]{index: -1}
+				if level < len(classifiers)-1 {
+					child.children = make(map[K]*groupTrieNode[K])
+				}
+				node.children[key] = child
+			}
+			node = child
+		}
+
+		if node.index < 0 {
+			node.index = len(result)
+			result = append(result, CompositeGroup[K, T]{Keys: slices.Clone(keys)})
+		}
+		result[node.index].Items = append(result[node.index].Items, item)
+	}
+	return result
+}
@@ -1,6 +1,7 @@
 package functional_test
 
 import (
+	"errors"
 	"fmt"
 	"math/rand" // For generating varied benchmark data
 	"reflect"
@@ -404,3 +405,324 @@ func BenchmarkGroupBy_Generic_ManyGroups_N10000(b *testing.B) {
 func BenchmarkGroupBy_Loop_ManyGroups_N10000(b *testing.B) {
 	benchmarkGroupByLoop(manyGroupsDataN2, keyFuncManyGroupsN2, b)
 }
+
+// --- Test Aggregating GroupBy Family ---
+
+type orderGroupTest struct {
+	ID     int
+	Region string
+	Amount float64
+}
+
+var groupAggOrders = []orderGroupTest{
+	{1, "eu", 10},
+	{2, "us", 5},
+	{3, "eu", 2.5},
+	{4, "apac", 7},
+	{5, "us", 1},
+}
+
+func regionOf(o orderGroupTest) string { return o.Region }
+
+func TestGroupByMap(t *testing.T) {
+	got := functional.GroupByMap(groupAggOrders, regionOf, func(o orderGroupTest) int { return o.ID })
+	want := map[string][]int{"eu": {1, 3}, "us": {2, 5}, "apac": {4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByMap() = %v, want %v", got, want)
+	}
+
+	empty := functional.GroupByMap([]orderGroupTest(nil), regionOf, func(o orderGroupTest) int { return o.ID })
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("GroupByMap(nil) = %#v, want empty non-nil map", empty)
+	}
+}
+
+func TestGroupByAgg(t *testing.T) {
+	t.Run("MaxPerGroup", func(t *testing.T) {
+		got := functional.GroupByAgg(groupAggOrders, regionOf, 0.0, func(acc float64, o orderGroupTest) float64 {
+			return max(acc, o.Amount)
+		})
+		want := map[string]float64{"eu": 10, "us": 5, "apac": 7}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GroupByAgg() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("FoldsInInputOrder", func(t *testing.T) {
+		got := functional.GroupByAgg([]string{"a1", "b1", "a2", "a3"},
+			func(s string) byte { return s[0] }, "",
+			func(acc, s string) string { return acc + s[1:] })
+		want := map[byte]string{'a': "123", 'b': "1"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GroupByAgg() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("NilInput", func(t *testing.T) {
+		got := functional.GroupByAgg([]int(nil), func(n int) int { return n }, 0, func(acc, n int) int { return acc + n })
+		if got == nil || len(got) != 0 {
+			t.Errorf("GroupByAgg(nil) = %#v, want empty non-nil map", got)
+		}
+	})
+}
+
+func TestCountBy(t *testing.T) {
+	got := functional.CountBy(groupAggOrders, regionOf)
+	want := map[string]int{"eu": 2, "us": 2, "apac": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CountBy() = %v, want %v", got, want)
+	}
+
+	empty := functional.CountBy([]int{}, func(n int) bool { return n > 0 })
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("CountBy(empty) = %#v, want empty non-nil map", empty)
+	}
+}
+
+func TestSumBy(t *testing.T) {
+	got := functional.SumBy(groupAggOrders, regionOf, func(o orderGroupTest) float64 { return o.Amount })
+	want := map[string]float64{"eu": 12.5, "us": 6, "apac": 7}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SumBy() = %v, want %v", got, want)
+	}
+
+	ints := functional.SumBy([]int{1, 2, 3, 4, 5}, func(n int) bool { return n%2 == 0 }, func(n int) int { return n })
+	if ints[true] != 6 || ints[false] != 9 {
+		t.Errorf("SumBy() ints = %v, want map[false:9 true:6]", ints)
+	}
+}
+
+func TestGroupByOrdered(t *testing.T) {
+	got := functional.GroupByOrdered(groupAggOrders, regionOf)
+	wantKeys := []string{"apac", "eu", "us"}
+	if len(got) != len(wantKeys) {
+		t.Fatalf("GroupByOrdered() returned %d groups, want %d", len(got), len(wantKeys))
+	}
+	for i, g := range got {
+		if g.Key != wantKeys[i] {
+			t.Errorf("GroupByOrdered()[%d].Key = %q, want %q", i, g.Key, wantKeys[i])
+		}
+	}
+	if want := []orderGroupTest{groupAggOrders[0], groupAggOrders[2]}; !reflect.DeepEqual(got[1].Items, want) {
+		t.Errorf("GroupByOrdered() eu items = %v, want %v", got[1].Items, want)
+	}
+
+	if empty := functional.GroupByOrdered([]int(nil), func(n int) int { return n }); len(empty) != 0 {
+		t.Errorf("GroupByOrdered(nil) = %v, want empty", empty)
+	}
+}
+
+func TestKeyBy(t *testing.T) {
+	input := []person{{"Alice", 30}, {"Bob", 25}, {"Alice", 41}}
+	byName := func(p person) string { return p.Name }
+
+	t.Run("KeepFirst", func(t *testing.T) {
+		got, err := functional.KeyBy(input, byName, functional.KeepFirst)
+		if err != nil {
+			t.Fatalf("KeyBy() unexpected error: %v", err)
+		}
+		want := map[string]person{"Alice": {"Alice", 30}, "Bob": {"Bob", 25}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("KeyBy() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("KeepLast", func(t *testing.T) {
+		got, err := functional.KeyBy(input, byName, functional.KeepLast)
+		if err != nil {
+			t.Fatalf("KeyBy() unexpected error: %v", err)
+		}
+		want := map[string]person{"Alice": {"Alice", 41}, "Bob": {"Bob", 25}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("KeyBy() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("ErrorOnCollision", func(t *testing.T) {
+		got, err := functional.KeyBy(input, byName, functional.ErrorOnCollision)
+		if !errors.Is(err, functional.ErrDuplicateKey) {
+			t.Fatalf("KeyBy() error = %v, want ErrDuplicateKey", err)
+		}
+		var dupErr *functional.DuplicateKeyError[string]
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("KeyBy() error type = %T, want *DuplicateKeyError[string]", err)
+		}
+		if dupErr.Key != "Alice" || dupErr.FirstIndex != 0 || dupErr.Index != 2 {
+			t.Errorf("KeyBy() error = %+v, want Key=Alice FirstIndex=0 Index=2", *dupErr)
+		}
+		if len(got) != 2 {
+			t.Errorf("KeyBy() partial map = %v, want the 2 entries indexed before the duplicate", got)
+		}
+	})
+
+	t.Run("ErrorOnCollision_Unique", func(t *testing.T) {
+		got, err := functional.KeyBy(input[:2], byName, functional.ErrorOnCollision)
+		if err != nil || len(got) != 2 {
+			t.Errorf("KeyBy() = %v, %v; want 2 entries and nil error", got, err)
+		}
+	})
+
+	t.Run("UnknownPolicyPanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("KeyBy() with unknown policy did not panic")
+			}
+		}()
+		_, _ = functional.KeyBy(input, byName, functional.CollisionPolicy(42))
+	})
+}
+
+func ExampleCountBy() {
+	words := []string{"go", "rust", "zig", "c", "java", "lua"}
+	counts := functional.CountBy(words, func(s string) int { return len(s) })
+
+	for _, length := range functional.SortedKeys(counts) {
+		fmt.Printf("%d letters: %d\n", length, counts[length])
+	}
+
+	// Output:
+	// 1 letters: 1
+	// 2 letters: 1
+	// 3 letters: 2
+	// 4 letters: 2
+}
+
+func ExampleGroupByAgg() {
+	type sale struct {
+		Product string
+		Units   int
+	}
+	sales := []sale{{"apple", 3}, {"pear", 1}, {"apple", 7}, {"pear", 4}}
+
+	// Track the largest single sale per product without building the groups.
+	largest := functional.GroupByAgg(sales, func(s sale) string { return s.Product }, 0,
+		func(acc int, s sale) int { return max(acc, s.Units) })
+
+	fmt.Println(largest["apple"], largest["pear"])
+
+	// Output:
+	// 7 4
+}
+
+// Benchmark SumBy against GroupBy followed by a manual sum per group.
+func BenchmarkSumBy_Generic_FewGroups_N10000(b *testing.B) {
+	valueOf := func(item groupByBenchItem) float64 { return item.Value }
+	b.ResetTimer()
+	var result map[string]float64
+	for i := 0; i < b.N; i++ {
+		result = functional.SumBy(fewGroupsDataN2, keyFuncFewGroups, valueOf)
+	}
+	_ = result
+}
+
+func BenchmarkSumBy_GroupByThenSum_FewGroups_N10000(b *testing.B) {
+	b.ResetTimer()
+	var result map[string]float64
+	for i := 0; i < b.N; i++ {
+		groups := functional.GroupBy(fewGroupsDataN2, keyFuncFewGroups)
+		sums := make(map[string]float64, len(groups))
+		for k, items := range groups {
+			for _, item := range items {
+				sums[k] += item.Value
+			}
+		}
+		result = sums
+	}
+	_ = result
+}
+
+// --- Test Multi-level GroupBy ---
+
+type saleGroupTest struct {
+	Region  string
+	Product string
+	Units   int
+}
+
+var multiLevelSales = []saleGroupTest{
+	{"eu", "apple", 3},
+	{"us", "apple", 5},
+	{"eu", "pear", 1},
+	{"eu", "apple", 2},
+	{"us", "plum", 4},
+}
+
+func TestGroupBy2(t *testing.T) {
+	got := functional.GroupBy2(multiLevelSales,
+		func(s saleGroupTest) string { return s.Region },
+		func(s saleGroupTest) string { return s.Product })
+	want := map[string]map[string][]saleGroupTest{
+		"eu": {
+			"apple": {multiLevelSales[0], multiLevelSales[3]},
+			"pear":  {multiLevelSales[2]},
+		},
+		"us": {
+			"apple": {multiLevelSales[1]},
+			"plum":  {multiLevelSales[4]},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBy2() = %v, want %v", got, want)
+	}
+
+	empty := functional.GroupBy2([]int(nil), func(n int) int { return n }, func(n int) int { return n })
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("GroupBy2(nil) = %#v, want empty non-nil map", empty)
+	}
+}
+
+func TestGroupByN(t *testing.T) {
+	t.Run("TwoLevels_FirstAppearanceOrder", func(t *testing.T) {
+		got := functional.GroupByN(multiLevelSales,
+			func(s saleGroupTest) string { return s.Region },
+			func(s saleGroupTest) string { return s.Product })
+		want := []functional.CompositeGroup[string, saleGroupTest]{
+			{Keys: []string{"eu", "apple"}, Items: []saleGroupTest{multiLevelSales[0], multiLevelSales[3]}},
+			{Keys: []string{"us", "apple"}, Items: []saleGroupTest{multiLevelSales[1]}},
+			{Keys: []string{"eu", "pear"}, Items: []saleGroupTest{multiLevelSales[2]}},
+			{Keys: []string{"us", "plum"}, Items: []saleGroupTest{multiLevelSales[4]}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GroupByN() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("ThreeLevels", func(t *testing.T) {
+		input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+		got := functional.GroupByN(input,
+			func(n int) int { return n % 2 },
+			func(n int) int { return n % 3 },
+			func(n int) int { return n / 5 })
+		total := 0
+		for _, g := range got {
+			if len(g.Keys) != 3 {
+				t.Fatalf("GroupByN() group %v has %d keys, want 3", g, len(g.Keys))
+			}
+			for _, n := range g.Items {
+				if n%2 != g.Keys[0] || n%3 != g.Keys[1] || n/5 != g.Keys[2] {
+					t.Errorf("GroupByN() element %d is in group with keys %v", n, g.Keys)
+				}
+			}
+			total += len(g.Items)
+		}
+		if total != len(input) {
+			t.Errorf("GroupByN() grouped %d elements, want %d", total, len(input))
+		}
+	})
+
+	t.Run("NilInput", func(t *testing.T) {
+		got := functional.GroupByN([]int(nil), func(n int) int { return n })
+		if got == nil || len(got) != 0 {
+			t.Errorf("GroupByN(nil) = %#v, want empty non-nil slice", got)
+		}
+	})
+
+	t.Run("NoClassifiersPanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("GroupByN() without classifiers did not panic")
+			}
+		}()
+		functional.GroupByN[int, int]([]int{1})
+	})
+}
@@ -0,0 +1,281 @@
+package functional
+
+import "cmp"
+
+// The join functions combine two slices on matching keys, like SQL joins.
+// The hash joins (InnerJoin, LeftJoin, FullOuterJoin, SemiJoin, AntiJoin)
+// index the right slice in a map and then scan the left slice, so they run in
+// O(len(left) + len(right) + matches) time and produce output in left order;
+// several matches for one left element appear in right order. InnerJoinSorted
+// is a sort-merge variant for inputs already sorted by key, which needs no map.
+
+// indexByKey maps every key of a slice to the positions that produced it, in
+// input order.
+func indexByKey[T any, K comparable](input []T, key func(T) K) map[K][]int {
+	index := make(map[K][]int, len(input))
+	for i, item := range input {
+		k := key(item)
+		index[k] = append(index[k], i)
+	}
+	return index
+}
+
+// InnerJoin returns one combined value for every pair of left and right
+// elements whose keys are equal.
+//
+// Type Parameters:
+//
+//	L: The type of elements in the left slice.
+//	R: The type of elements in the right slice.
+//	K: The join key type (must be comparable).
+//	O: The type of the combined output.
+//
+// Parameters:
+//
+//	left:     The driving slice, such as orders. Can be nil or empty.
+//	right:    The slice to look up, such as customers. Can be nil or empty.
+//	leftKey:  A function that returns the join key of a left element.
+//	rightKey: A function that returns the join key of a right element.
+//	combine:  A function that builds the output for a matching pair.
+//
+// Returns:
+//
+//	[]O: The combined values in left order. Left elements without a match are
+//	     dropped. Returns an empty slice if nothing matches.
+func InnerJoin[L, R any, K comparable, O any](
+	left []L, right []R, leftKey func(L) K, rightKey func(R) K, combine func(l L, r R) O,
+) []O {
+	result := make([]O, 0)
+	if len(left) == 0 || len(right) == 0 {
+		return result
+	}
+	index := indexByKey(right, rightKey)
+	for _, l := range left {
+		for _, j := range index[leftKey(l)] {
+			result = append(result, combine(l, right[j]))
+		}
+	}
+	return result
+}
+
+// LeftJoin returns one combined value for every pair of matching left and
+// right elements, plus one for every left element without a match.
+//
+// Type Parameters:
+//
+//	L: The type of elements in the left slice.
+//	R: The type of elements in the right slice.
+//	K: The join key type (must be comparable).
+//	O: The type of the combined output.
+//
+// Parameters:
+//
+//	left:     The driving slice. Every element appears in the output at least
+//	          once. Can be nil or empty.
+//	right:    The slice to look up. Can be nil or empty.
+//	leftKey:  A function that returns the join key of a left element.
+//	rightKey: A function that returns the join key of a right element.
+//	combine:  A function that builds the output. r points to the matching
+//	          element within the right slice, or is nil if there is none.
+//
+// Returns:
+//
+//	[]O: The combined values in left order. Returns an empty slice if left is
+//	     nil or empty.
+func LeftJoin[L, R any, K comparable, O any](
+	left []L, right []R, leftKey func(L) K, rightKey func(R) K, combine func(l L, r *R) O,
+) []O {
+	result := make([]O, 0, len(left))
+	index := indexByKey(right, rightKey)
+	for _, l := range left {
+		matches := index[leftKey(l)]
+		if len(matches) == 0 {
+			result = append(result, combine(l, nil))
+			continue
+		}
+		for _, j := range matches {
+			result = append(result, combine(l, &right[j]))
+		}
+	}
+	return result
+}
+
+// FullOuterJoin returns one combined value for every pair of matching left and
+// right elements, plus one for every element on either side without a match.
+//
+// Type Parameters:
+//
+//	L: The type of elements in the left slice.
+//	R: The type of elements in the right slice.
+//	K: The join key type (must be comparable).
+//	O: The type of the combined output.
+//
+// Parameters:
+//
+//	left:     The first slice. Can be nil or empty.
+//	right:    The second slice. Can be nil or empty.
+//	leftKey:  A function that returns the join key of a left element.
+//	rightKey: A function that returns the join key of a right element.
+//	combine:  A function that builds the output. l and r point into the input
+//	          slices; exactly one of them is nil for unmatched elements.
+//
+// Returns:
+//
+//	[]O: The matched and left-only values in left order, followed by the
+//	     right-only values in right order. Returns an empty slice if both
+//	     inputs are nil or empty.
+func FullOuterJoin[L, R any, K comparable, O any](
+	left []L, right []R, leftKey func(L) K, rightKey func(R) K, combine func(l *L, r *R) O,
+) []O {
+	result := make([]O, 0, max(len(left), len(right)))
+	index := indexByKey(right, rightKey)
+	matched := make([]bool, len(right))
+	for i := range left {
+		matches := index[leftKey(left[i])]
+		if len(matches) == 0 {
+			result = append(result, combine(&left[i], nil))
+			continue
+		}
+		for _, j := range matches {
+			matched[j] = true
+			result = append(result, combine(&left[i], &right[j]))
+		}
+	}
+	for j := range right {
+		if !matched[j] {
+			result = append(result, combine(nil, &right[j]))
+		}
+	}
+	return result
+}
+
+// SemiJoin returns the left elements that have at least one matching right
+// element. Each left element appears at most once, however many matches it has.
+//
+// Type Parameters:
+//
+//	L: The type of elements in the left slice.
+//	R: The type of elements in the right slice.
+//	K: The join key type (must be comparable).
+//
+// Parameters:
+//
+//	left:     The slice to filter. Can be nil or empty.
+//	right:    The slice whose keys are looked up. Can be nil or empty.
+//	leftKey:  A function that returns the join key of a left element.
+//	rightKey: A function that returns the join key of a right element.
+//
+// Returns:
+//
+//	[]L: The matching left elements in left order. Returns an empty slice if
+//	     nothing matches.
+func SemiJoin[L, R any, K comparable](left []L, right []R, leftKey func(L) K, rightKey func(R) K) []L {
+	keys := rightKeySet(right, rightKey)
+	return Filter(left, func(l L) bool {
+		_, ok := keys[leftKey(l)]
+		return ok
+	})
+}
+
+// AntiJoin returns the left elements that have no matching right element,
+// such as customers without orders.
+//
+// Type Parameters:
+//
+//	L: The type of elements in the left slice.
+//	R: The type of elements in the right slice.
+//	K: The join key type (must be comparable).
+//
+// Parameters:
+//
+//	left:     The slice to filter. Can be nil or empty.
+//	right:    The slice whose keys are looked up. Can be nil or empty.
+//	leftKey:  A function that returns the join key of a left element.
+//	rightKey: A function that returns the join key of a right element.
+//
+// Returns:
+//
+//	[]L: The unmatched left elements in left order. Returns an empty slice if
+//	     every left element has a match.
+func AntiJoin[L, R any, K comparable](left []L, right []R, leftKey func(L) K, rightKey func(R) K) []L {
+	keys := rightKeySet(right, rightKey)
+	return Filter(left, func(l L) bool {
+		_, ok := keys[leftKey(l)]
+		return !ok
+	})
+}
+
+// rightKeySet collects the distinct keys of a slice.
+func rightKeySet[R any, K comparable](right []R, rightKey func(R) K) map[K]struct{} {
+	keys := make(map[K]struct{}, len(right))
+	for _, r := range right {
+		keys[rightKey(r)] = struct{}{}
+	}
+	return keys
+}
+
+// InnerJoinSorted is the sort-merge variant of InnerJoin for inputs that are
+// already sorted in ascending key order. It walks both slices once without
+// building a map, in O(len(left) + len(right) + matches) time and O(1) extra
+// space, which suits large pre-sorted inputs such as database exports.
+//
+// Type Parameters:
+//
+//	L: The type of elements in the left slice.
+//	R: The type of elements in the right slice.
+//	K: The join key type. Must be ordered.
+//	O: The type of the combined output.
+//
+// Parameters:
+//
+//	left:     The driving slice, sorted ascending by leftKey.
+//	right:    The other slice, sorted ascending by rightKey.
+//	leftKey:  A function that returns the join key of a left element.
+//	rightKey: A function that returns the join key of a right element.
+//	combine:  A function that builds the output for a matching pair.
+//
+// Returns:
+//
+//	[]O: The same values as InnerJoin, in the same order (left order, then
+//	     right order within a key). As there, NaN keys match nothing. If
+//	     either input is not sorted the result is unspecified; check with
+//	     IsSortedBy when unsure.
+func InnerJoinSorted[L, R any, K cmp.Ordered, O any](
+	left []L, right []R, leftKey func(L) K, rightKey func(R) K, combine func(l L, r R) O,
+) []O {
+	result := make([]O, 0)
+	i, j := 0, 0
+	for i < len(left) && j < len(right) {
+		lk, rk := leftKey(left[i]), rightKey(right[j])
+		// cmp.Compare treats NaN keys as equal to each other, but a map never
+		// matches them, so skip them to agree with InnerJoin.
+		if lk != lk {
+			i++
+			continue
+		}
+		if rk != rk {
+			j++
+			continue
+		}
+		switch c := cmp.Compare(lk, rk); {
+		case c < 0:
+			i++
+		case c > 0:
+			j++
+		default:
+			// Find the run of equal keys on the right, then pair every left
+			// element of the matching run with it.
+			runEnd := j + 1
+			for runEnd < len(right) && cmp.Compare(rightKey(right[runEnd]), lk) == 0 {
+				runEnd++
+			}
+			for ; i < len(left) && cmp.Compare(leftKey(left[i]), lk) == 0; i++ {
+				for _, r := range right[j:runEnd] {
+					result = append(result, combine(left[i], r))
+				}
+			}
+			j = runEnd
+		}
+	}
+	return result
+}
@@ -0,0 +1,232 @@
+package functional_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+type joinOrder struct {
+	ID         int
+	CustomerID int
+}
+
+type joinCustomer struct {
+	ID   int
+	Name string
+}
+
+var (
+	joinOrders = []joinOrder{{100, 2}, {101, 1}, {102, 9}, {103, 2}}
+	// Customer 2 appears twice to exercise one-to-many matches.
+	joinCustomers = []joinCustomer{{1, "ann"}, {2, "bob"}, {3, "cy"}, {2, "bob-dup"}}
+)
+
+func orderCustomerID(o joinOrder) int              { return o.CustomerID }
+func customerID(c joinCustomer) int                { return c.ID }
+func orderOf(o joinOrder) string                   { return fmt.Sprint(o.ID) }
+func pairLabel(o joinOrder, c joinCustomer) string { return fmt.Sprintf("%d:%s", o.ID, c.Name) }
+
+func TestInnerJoin(t *testing.T) {
+	got := functional.InnerJoin(joinOrders, joinCustomers, orderCustomerID, customerID, pairLabel)
+	want := []string{"100:bob", "100:bob-dup", "101:ann", "103:bob", "103:bob-dup"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InnerJoin() = %v, want %v", got, want)
+	}
+
+	if got := functional.InnerJoin(nil, joinCustomers, orderCustomerID, customerID, pairLabel); got == nil || len(got) != 0 {
+		t.Errorf("InnerJoin(nil, ...) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestLeftJoin(t *testing.T) {
+	got := functional.LeftJoin(joinOrders, joinCustomers[:3], orderCustomerID, customerID,
+		func(o joinOrder, c *joinCustomer) string {
+			if c == nil {
+				return orderOf(o) + ":<none>"
+			}
+			return pairLabel(o, *c)
+		})
+	want := []string{"100:bob", "101:ann", "102:<none>", "103:bob"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LeftJoin() = %v, want %v", got, want)
+	}
+
+	t.Run("PointsIntoRightSlice", func(t *testing.T) {
+		customers := slices.Clone(joinCustomers[:1])
+		functional.LeftJoin([]joinOrder{{1, 1}}, customers, orderCustomerID, customerID,
+			func(_ joinOrder, c *joinCustomer) int {
+				if c != &customers[0] {
+					t.Error("LeftJoin() should pass a pointer into the right slice")
+				}
+				return 0
+			})
+	})
+}
+
+func TestFullOuterJoin(t *testing.T) {
+	got := functional.FullOuterJoin(joinOrders, joinCustomers[:3], orderCustomerID, customerID,
+		func(o *joinOrder, c *joinCustomer) string {
+			switch {
+			case o == nil:
+				return "<none>:" + c.Name
+			case c == nil:
+				return orderOf(*o) + ":<none>"
+			default:
+				return pairLabel(*o, *c)
+			}
+		})
+	want := []string{"100:bob", "101:ann", "102:<none>", "103:bob", "<none>:cy"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FullOuterJoin() = %v, want %v", got, want)
+	}
+
+	empty := functional.FullOuterJoin([]joinOrder{}, []joinCustomer(nil), orderCustomerID, customerID,
+		func(*joinOrder, *joinCustomer) int { return 0 })
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("FullOuterJoin() of empty inputs = %#v, want empty non-nil slice", empty)
+	}
+}
+
+func TestSemiJoinAntiJoin(t *testing.T) {
+	semi := functional.SemiJoin(joinCustomers[:3], joinOrders, customerID, orderCustomerID)
+	if want := []joinCustomer{{1, "ann"}, {2, "bob"}}; !reflect.DeepEqual(semi, want) {
+		t.Errorf("SemiJoin() = %v, want %v", semi, want)
+	}
+
+	anti := functional.AntiJoin(joinCustomers[:3], joinOrders, customerID, orderCustomerID)
+	if want := []joinCustomer{{3, "cy"}}; !reflect.DeepEqual(anti, want) {
+		t.Errorf("AntiJoin() = %v, want %v", anti, want)
+	}
+
+	if all := functional.AntiJoin(joinCustomers, []joinOrder(nil), customerID, orderCustomerID); !reflect.DeepEqual(all, joinCustomers) {
+		t.Errorf("AntiJoin() against nothing = %v, want all of left", all)
+	}
+}
+
+func TestInnerJoinSorted(t *testing.T) {
+	t.Run("MatchesHashJoin", func(t *testing.T) {
+		orders := slices.Clone(joinOrders)
+		customers := slices.Clone(joinCustomers)
+		functional.SortStableBy(orders, orderCustomerID)
+		functional.SortStableBy(customers, customerID)
+
+		got := functional.InnerJoinSorted(orders, customers, orderCustomerID, customerID, pairLabel)
+		want := functional.InnerJoin(orders, customers, orderCustomerID, customerID, pairLabel)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("InnerJoinSorted() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("RandomDuplicates", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(3))
+		left := make([]int, 200)
+		right := make([]int, 150)
+		for i := range left {
+			left[i] = rng.Intn(40)
+		}
+		for i := range right {
+			right[i] = rng.Intn(40)
+		}
+		slices.Sort(left)
+		slices.Sort(right)
+
+		identity := func(n int) int { return n }
+		pair := func(l, r int) [2]int { return [2]int{l, r} }
+		got := functional.InnerJoinSorted(left, right, identity, identity, pair)
+		want := functional.InnerJoin(left, right, identity, identity, pair)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("InnerJoinSorted() produced %d pairs, hash join %d", len(got), len(want))
+		}
+	})
+
+	t.Run("NaNKeys", func(t *testing.T) {
+		// slices.Sort puts NaNs first; neither join may pair them.
+		left := []float64{math.NaN(), math.NaN(), 1, 2}
+		right := []float64{math.NaN(), 2, 3}
+		identity := func(f float64) float64 { return f }
+		pair := func(l, r float64) string { return fmt.Sprint(l, "-", r) }
+		got := functional.InnerJoinSorted(left, right, identity, identity, pair)
+		want := functional.InnerJoin(left, right, identity, identity, pair)
+		if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(got, []string{"2-2"}) {
+			t.Errorf("InnerJoinSorted() = %v, InnerJoin() = %v, want [2-2]", got, want)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		got := functional.InnerJoinSorted([]joinOrder{}, joinCustomers, orderCustomerID, customerID, pairLabel)
+		if got == nil || len(got) != 0 {
+			t.Errorf("InnerJoinSorted() of empty left = %#v, want empty non-nil slice", got)
+		}
+	})
+}
+
+func ExampleLeftJoin() {
+	type order struct {
+		ID         int
+		CustomerID int
+	}
+	type customer struct {
+		ID   int
+		Name string
+	}
+	orders := []order{{1, 10}, {2, 20}, {3, 10}}
+	customers := []customer{{10, "Ann"}}
+
+	lines := functional.LeftJoin(orders, customers,
+		func(o order) int { return o.CustomerID },
+		func(c customer) int { return c.ID },
+		func(o order, c *customer) string {
+			if c == nil {
+				return fmt.Sprintf("order %d: unknown customer", o.ID)
+			}
+			return fmt.Sprintf("order %d: %s", o.ID, c.Name)
+		})
+
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	// Output:
+	// order 1: Ann
+	// order 2: unknown customer
+	// order 3: Ann
+}
+
+// --- Benchmarks ---
+
+func generateJoinData(size int) ([]joinOrder, []joinCustomer) {
+	rng := rand.New(rand.NewSource(11))
+	customers := make([]joinCustomer, size/10)
+	for i := range customers {
+		customers[i] = joinCustomer{ID: i, Name: fmt.Sprint("c", i)}
+	}
+	orders := make([]joinOrder, size)
+	for i := range orders {
+		orders[i] = joinOrder{ID: i, CustomerID: rng.Intn(len(customers))}
+	}
+	return orders, customers
+}
+
+func BenchmarkInnerJoin_Hash_N10000(b *testing.B) {
+	orders, customers := generateJoinData(10000)
+	combine := func(o joinOrder, c joinCustomer) int { return o.ID + c.ID }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = functional.InnerJoin(orders, customers, orderCustomerID, customerID, combine)
+	}
+}
+
+func BenchmarkInnerJoin_SortMerge_N10000(b *testing.B) {
+	orders, customers := generateJoinData(10000)
+	functional.SortBy(orders, orderCustomerID)
+	combine := func(o joinOrder, c joinCustomer) int { return o.ID + c.ID }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = functional.InnerJoinSorted(orders, customers, orderCustomerID, customerID, combine)
+	}
+}
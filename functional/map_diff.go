@@ -0,0 +1,158 @@
+package functional
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Entry is a single key-value pair of a map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Change records a key whose value differs between two maps.
+type Change[K comparable, V any] struct {
+	Key K
+	Old V
+	New V
+}
+
+// MapDiff describes how to turn one map into another: which keys were added,
+// which were removed, and which now hold a different value. It is produced by
+// DiffMaps, DiffMapsFunc and DiffMapsOrdered.
+//
+// The order of the entries in each slice follows map iteration order and is
+// not guaranteed, unless the diff came from DiffMapsOrdered or Sorted.
+type MapDiff[K comparable, V any] struct {
+	Added   []Entry[K, V]  // Keys only in the new map, with their new values.
+	Removed []Entry[K, V]  // Keys only in the old map, with their old values.
+	Changed []Change[K, V] // Keys in both maps whose values are not equal.
+}
+
+// DiffMaps compares two maps with comparable values and returns the entries
+// that were added, removed or changed going from oldMap to newMap.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the map values (must be comparable).
+//
+// Parameters:
+//
+//	oldMap: The starting state, such as the actual state. Can be nil.
+//	newMap: The target state, such as the desired state. Can be nil.
+//
+// Returns:
+//
+//	MapDiff[K, V]: The differences. All slices are empty (non-nil) if the maps
+//	               hold the same entries.
+func DiffMaps[K, V comparable](oldMap, newMap map[K]V) MapDiff[K, V] {
+	return DiffMapsFunc(oldMap, newMap, func(a, b V) bool { return a == b })
+}
+
+// DiffMapsFunc compares two maps like DiffMaps, using an equality callback so
+// that values which are not comparable (slices, maps, structs holding them)
+// can be diffed.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the map values.
+//
+// Parameters:
+//
+//	oldMap: The starting state. Can be nil.
+//	newMap: The target state. Can be nil.
+//	equal:  Reports whether two values are the same. It is called once for
+//	        every key present in both maps.
+//
+// Returns:
+//
+//	MapDiff[K, V]: The differences. All slices are empty (non-nil) if the maps
+//	               hold the same entries.
+func DiffMapsFunc[K comparable, V any](oldMap, newMap map[K]V, equal func(a, b V) bool) MapDiff[K, V] {
+	diff := MapDiff[K, V]{
+		Added:   make([]Entry[K, V], 0),
+		Removed: make([]Entry[K, V], 0),
+		Changed: make([]Change[K, V], 0),
+	}
+
+	for k, oldValue := range oldMap {
+		newValue, ok := newMap[k]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, Entry[K, V]{Key: k, Value: oldValue})
+		case !equal(oldValue, newValue):
+			diff.Changed = append(diff.Changed, Change[K, V]{Key: k, Old: oldValue, New: newValue})
+		}
+	}
+	for k, newValue := range newMap {
+		if _, ok := oldMap[k]; !ok {
+			diff.Added = append(diff.Added, Entry[K, V]{Key: k, Value: newValue})
+		}
+	}
+	return diff
+}
+
+// DiffMapsOrdered compares two maps like DiffMaps and sorts every part of the
+// result by key, so the output is deterministic and suitable for reports and
+// golden tests.
+func DiffMapsOrdered[K cmp.Ordered, V comparable](oldMap, newMap map[K]V) MapDiff[K, V] {
+	return DiffMaps(oldMap, newMap).Sorted(cmp.Compare[K])
+}
+
+// IsEmpty reports whether the diff has no additions, removals or changes.
+func (d MapDiff[K, V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Sorted returns a copy of the diff with every part sorted by key using
+// compare. The receiver is not modified.
+func (d MapDiff[K, V]) Sorted(compare func(a, b K) int) MapDiff[K, V] {
+	byEntryKey := func(a, b Entry[K, V]) int { return compare(a.Key, b.Key) }
+	result := MapDiff[K, V]{
+		Added:   slices.Clone(d.Added),
+		Removed: slices.Clone(d.Removed),
+		Changed: slices.Clone(d.Changed),
+	}
+	slices.SortFunc(result.Added, byEntryKey)
+	slices.SortFunc(result.Removed, byEntryKey)
+	slices.SortFunc(result.Changed, func(a, b Change[K, V]) int { return compare(a.Key, b.Key) })
+	return result
+}
+
+// Apply patches base forward and returns the result as a new map: removed keys
+// are deleted, and added and changed keys are set to their new values. Applying
+// DiffMaps(a, b) to a yields a map equal to b. The base map is not modified.
+//
+// Apply does not check that base matches the old state the diff was computed
+// from; keys that are already absent are simply not deleted.
+func (d MapDiff[K, V]) Apply(base map[K]V) map[K]V {
+	result := Clone(base)
+	for _, e := range d.Removed {
+		delete(result, e.Key)
+	}
+	for _, e := range d.Added {
+		result[e.Key] = e.Value
+	}
+	for _, c := range d.Changed {
+		result[c.Key] = c.New
+	}
+	return result
+}
+
+// Reverse returns the diff that undoes d: additions become removals, removals
+// become additions, and every change swaps its old and new values. Applying
+// DiffMaps(a, b).Reverse() to b yields a map equal to a.
+func (d MapDiff[K, V]) Reverse() MapDiff[K, V] {
+	changed := make([]Change[K, V], len(d.Changed))
+	for i, c := range d.Changed {
+		changed[i] = Change[K, V]{Key: c.Key, Old: c.New, New: c.Old}
+	}
+	return MapDiff[K, V]{
+		Added:   slices.Clone(d.Removed),
+		Removed: slices.Clone(d.Added),
+		Changed: changed,
+	}
+}
@@ -0,0 +1,127 @@
+package functional_test
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+var (
+	diffActual  = map[string]int{"web": 3, "worker": 2, "cron": 1}
+	diffDesired = map[string]int{"web": 5, "worker": 2, "api": 4}
+)
+
+func TestDiffMapsOrdered(t *testing.T) {
+	got := functional.DiffMapsOrdered(diffActual, diffDesired)
+	want := functional.MapDiff[string, int]{
+		Added:   []functional.Entry[string, int]{{Key: "api", Value: 4}},
+		Removed: []functional.Entry[string, int]{{Key: "cron", Value: 1}},
+		Changed: []functional.Change[string, int]{{Key: "web", Old: 3, New: 5}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffMapsOrdered() = %+v, want %+v", got, want)
+	}
+	if got.IsEmpty() {
+		t.Error("IsEmpty() = true for a non-empty diff")
+	}
+}
+
+func TestDiffMaps_Identical(t *testing.T) {
+	for name, pair := range map[string][2]map[string]int{
+		"Equal":    {diffActual, functional.Clone(diffActual)},
+		"BothNil":  {nil, nil},
+		"NilEmpty": {nil, {}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := functional.DiffMaps(pair[0], pair[1])
+			if !got.IsEmpty() {
+				t.Errorf("DiffMaps() = %+v, want empty diff", got)
+			}
+			if got.Added == nil || got.Removed == nil || got.Changed == nil {
+				t.Errorf("DiffMaps() = %#v, want empty non-nil slices", got)
+			}
+		})
+	}
+}
+
+func TestDiffMaps_FromNil(t *testing.T) {
+	got := functional.DiffMapsOrdered(nil, map[int]bool{2: true, 1: false})
+	wantAdded := []functional.Entry[int, bool]{{Key: 1, Value: false}, {Key: 2, Value: true}}
+	if !reflect.DeepEqual(got.Added, wantAdded) || len(got.Removed) != 0 || len(got.Changed) != 0 {
+		t.Errorf("DiffMapsOrdered(nil, m) = %+v, want only additions %v", got, wantAdded)
+	}
+}
+
+func TestDiffMapsFunc(t *testing.T) {
+	oldMap := map[string][]string{"a": {"x", "y"}, "b": {"z"}}
+	newMap := map[string][]string{"a": {"x", "y"}, "b": {"z", "w"}}
+
+	got := functional.DiffMapsFunc(oldMap, newMap, slices.Equal[[]string])
+	if len(got.Added) != 0 || len(got.Removed) != 0 || len(got.Changed) != 1 {
+		t.Fatalf("DiffMapsFunc() = %+v, want exactly one change", got)
+	}
+	if c := got.Changed[0]; c.Key != "b" || !reflect.DeepEqual(c.Old, []string{"z"}) || !reflect.DeepEqual(c.New, []string{"z", "w"}) {
+		t.Errorf("DiffMapsFunc() change = %+v, want b: [z] -> [z w]", c)
+	}
+}
+
+func TestMapDiff_ApplyAndReverse(t *testing.T) {
+	diff := functional.DiffMaps(diffActual, diffDesired)
+
+	forward := diff.Apply(diffActual)
+	if !reflect.DeepEqual(forward, diffDesired) {
+		t.Errorf("Apply() = %v, want %v", forward, diffDesired)
+	}
+	if len(diffActual) != 3 || diffActual["web"] != 3 {
+		t.Errorf("Apply() modified its base: %v", diffActual)
+	}
+
+	back := diff.Reverse().Apply(diffDesired)
+	if !reflect.DeepEqual(back, diffActual) {
+		t.Errorf("Reverse().Apply() = %v, want %v", back, diffActual)
+	}
+
+	if twice := diff.Reverse().Reverse().Sorted(func(a, b string) int { return len(a) - len(b) }); len(twice.Changed) != 1 || twice.Changed[0].Old != 3 {
+		t.Errorf("Reverse().Reverse() = %+v, want the original change", twice)
+	}
+
+	if applied := (functional.MapDiff[string, int]{}).Apply(nil); applied == nil || len(applied) != 0 {
+		t.Errorf("empty diff Apply(nil) = %#v, want empty non-nil map", applied)
+	}
+}
+
+func TestMapDiff_SortedDoesNotModifyReceiver(t *testing.T) {
+	diff := functional.MapDiff[int, int]{
+		Added: []functional.Entry[int, int]{{Key: 3}, {Key: 1}, {Key: 2}},
+	}
+	sorted := diff.Sorted(func(a, b int) int { return a - b })
+	if keys := functional.Map(sorted.Added, func(e functional.Entry[int, int]) int { return e.Key }); !reflect.DeepEqual(keys, []int{1, 2, 3}) {
+		t.Errorf("Sorted() keys = %v, want [1 2 3]", keys)
+	}
+	if diff.Added[0].Key != 3 {
+		t.Error("Sorted() modified the receiver")
+	}
+}
+
+func ExampleDiffMapsOrdered() {
+	actual := map[string]int{"web": 3, "worker": 2, "cron": 1}
+	desired := map[string]int{"web": 5, "worker": 2, "api": 4}
+
+	diff := functional.DiffMapsOrdered(actual, desired)
+	for _, e := range diff.Added {
+		fmt.Printf("+ %s=%d\n", e.Key, e.Value)
+	}
+	for _, e := range diff.Removed {
+		fmt.Printf("- %s=%d\n", e.Key, e.Value)
+	}
+	for _, c := range diff.Changed {
+		fmt.Printf("~ %s: %d -> %d\n", c.Key, c.Old, c.New)
+	}
+	// Output:
+	// + api=4
+	// - cron=1
+	// ~ web: 3 -> 5
+}
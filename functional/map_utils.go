@@ -1,5 +1,10 @@
 package functional
 
+import (
+	"cmp"
+	"slices"
+)
+
 // Import 'sort' only if needed by other functions in this file.
 // If Keys was the only user, 'sort' can be removed.
 // import "sort"
@@ -124,3 +129,310 @@ func MapToSlice[K comparable, V, R any](inputMap map[K]V, fn func(k K, v V) R) [
 
 	return result
 }
+
+// SortedKeys extracts the keys from a map into a slice sorted in ascending
+// order. It is the deterministic counterpart of Keys for ordered key types and
+// is handy for iterating over the maps returned by GroupBy, CountBy and SumBy.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys. Must be ordered.
+//	V: The type of the map values (any).
+//
+// Parameters:
+//
+//	inputMap: The map from which to extract keys. Can be nil.
+//
+// Returns:
+//
+//	[]K: A slice containing the keys in ascending order. Returns an empty slice
+//	     if the input map is nil or empty.
+func SortedKeys[K cmp.Ordered, V any](inputMap map[K]V) []K {
+	keys := Keys(inputMap)
+	slices.Sort(keys)
+	return keys
+}
+
+// MapValues transforms every value of a map, keeping the keys.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the input map values.
+//	R: The type of the output map values.
+//
+// Parameters:
+//
+//	inputMap: The map to transform. Can be nil.
+//	fn:       A function that takes a key and its value and returns the new value.
+//
+// Returns:
+//
+//	map[K]R: A new map with the same keys and transformed values. Returns an
+//	         empty, non-nil map if the input map is nil or empty.
+func MapValues[K comparable, V, R any](inputMap map[K]V, fn func(k K, v V) R) map[K]R {
+	// Preallocate with the exact size; every key is kept.
+	result := make(map[K]R, len(inputMap))
+	for k, v := range inputMap {
+		result[k] = fn(k, v)
+	}
+	return result
+}
+
+// MapKeys transforms every key of a map, keeping the values. Distinct input
+// keys can map to the same output key; resolve decides which value survives.
+//
+// Type Parameters:
+//
+//	K: The type of the input map keys (must be comparable).
+//	R: The type of the output map keys (must be comparable).
+//	V: The type of the map values.
+//
+// Parameters:
+//
+//	inputMap: The map to transform. Can be nil.
+//	fn:       A function that takes a key and its value and returns the new key.
+//	resolve:  Called on a collision with the new key, the value already stored
+//	          and the incoming value; it returns the value to keep. Because map
+//	          iteration order is random, resolve should not depend on argument
+//	          order (for example, keep the larger value). If nil, an arbitrary
+//	          one of the colliding values is kept.
+//
+// Returns:
+//
+//	map[R]V: A new map with transformed keys. Returns an empty, non-nil map if
+//	         the input map is nil or empty.
+func MapKeys[K, R comparable, V any](
+	inputMap map[K]V, fn func(k K, v V) R, resolve func(key R, existing, incoming V) V,
+) map[R]V {
+	// The result has at most len(inputMap) keys; fewer if keys collide.
+	result := make(map[R]V, len(inputMap))
+	for k, v := range inputMap {
+		newKey := fn(k, v)
+		if existing, ok := result[newKey]; ok && resolve != nil {
+			v = resolve(newKey, existing, v)
+		}
+		result[newKey] = v
+	}
+	return result
+}
+
+// FilterMap returns a new map containing only the entries of the input map
+// that satisfy the predicate.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the map values.
+//
+// Parameters:
+//
+//	inputMap:  The map to filter. Can be nil.
+//	predicate: A function that takes a key and its value and reports whether
+//	           the entry should be kept.
+//
+// Returns:
+//
+//	map[K]V: A new map with the kept entries. Returns an empty, non-nil map if
+//	         the input map is nil or empty, or if no entry is kept.
+func FilterMap[K comparable, V any](inputMap map[K]V, predicate func(k K, v V) bool) map[K]V {
+	// Like Filter, the result size is unknown, so start without a size hint.
+	result := make(map[K]V)
+	for k, v := range inputMap {
+		if predicate(k, v) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Invert swaps the keys and values of a map.
+//
+// Type Parameters:
+//
+//	K: The type of the input map keys (must be comparable).
+//	V: The type of the input map values (must be comparable).
+//
+// Parameters:
+//
+//	inputMap: The map to invert. Can be nil.
+//
+// Returns:
+//
+//	map[V]K: A new map from each value to a key that held it. If several keys
+//	         share a value, which key is kept is not guaranteed; use
+//	         InvertMulti to keep all of them. Returns an empty, non-nil map if
+//	         the input map is nil or empty.
+func Invert[K, V comparable](inputMap map[K]V) map[V]K {
+	result := make(map[V]K, len(inputMap))
+	for k, v := range inputMap {
+		result[v] = k
+	}
+	return result
+}
+
+// InvertMulti swaps the keys and values of a map, collecting every key that
+// held a value.
+//
+// Type Parameters:
+//
+//	K: The type of the input map keys (must be comparable).
+//	V: The type of the input map values (must be comparable).
+//
+// Parameters:
+//
+//	inputMap: The map to invert. Can be nil.
+//
+// Returns:
+//
+//	map[V][]K: A new map from each value to all keys that held it. The order
+//	           of keys within each slice is not guaranteed. Returns an empty,
+//	           non-nil map if the input map is nil or empty.
+func InvertMulti[K, V comparable](inputMap map[K]V) map[V][]K {
+	result := make(map[V][]K)
+	for k, v := range inputMap {
+		result[v] = append(result[v], k)
+	}
+	return result
+}
+
+// MergeWith merges any number of maps into a new map, from left to right.
+// When a key is present in more than one map, resolve combines the value
+// merged so far with the value from the next map.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the map values.
+//
+// Parameters:
+//
+//	resolve: Called with the key, the value merged so far and the value from
+//	         the later map; it returns the value to keep. If nil, the value
+//	         from the later map wins.
+//	maps:    The maps to merge. Nil maps are skipped.
+//
+// Returns:
+//
+//	map[K]V: A new map containing every key from every input. Returns an empty,
+//	         non-nil map if no maps are given or all are nil or empty.
+//
+// Because maps are applied in argument order, the result is deterministic as
+// long as resolve is.
+func MergeWith[K comparable, V any](resolve func(k K, a, b V) V, maps ...map[K]V) map[K]V {
+	// Size for the largest input: a lower bound on the result size.
+	capacityHint := 0
+	for _, m := range maps {
+		capacityHint = max(capacityHint, len(m))
+	}
+
+	result := make(map[K]V, capacityHint)
+	for _, m := range maps {
+		for k, v := range m {
+			if existing, ok := result[k]; ok && resolve != nil {
+				v = resolve(k, existing, v)
+			}
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Pick returns a new map containing only the given keys of the input map.
+// Keys that are not present in the input are ignored.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the map values.
+//
+// Parameters:
+//
+//	inputMap: The map to pick from. Can be nil.
+//	keys:     The keys to keep.
+//
+// Returns:
+//
+//	map[K]V: A new map with the picked entries. Returns an empty, non-nil map
+//	         if none of the keys are present.
+func Pick[K comparable, V any](inputMap map[K]V, keys ...K) map[K]V {
+	result := make(map[K]V, min(len(keys), len(inputMap)))
+	for _, k := range keys {
+		if v, ok := inputMap[k]; ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Omit returns a new map containing every entry of the input map except the
+// given keys.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the map values.
+//
+// Parameters:
+//
+//	inputMap: The map to copy. Can be nil.
+//	keys:     The keys to leave out.
+//
+// Returns:
+//
+//	map[K]V: A new map without the omitted keys. Returns an empty, non-nil map
+//	         if the input map is nil or empty.
+func Omit[K comparable, V any](inputMap map[K]V, keys ...K) map[K]V {
+	result := Clone(inputMap)
+	for _, k := range keys {
+		delete(result, k)
+	}
+	return result
+}
+
+// GetOrDefault returns the value stored under key, or defaultValue if the key
+// is not present. A nil map is treated as empty.
+func GetOrDefault[K comparable, V any](inputMap map[K]V, key K, defaultValue V) V {
+	if v, ok := inputMap[key]; ok {
+		return v
+	}
+	return defaultValue
+}
+
+// Update sets the value stored under key to the result of fn, modifying the
+// map in place. fn receives the current value and whether the key was present,
+// so the same call can insert or update.
+//
+// Type Parameters:
+//
+//	K: The type of the map keys (must be comparable).
+//	V: The type of the map values.
+//
+// Parameters:
+//
+//	inputMap: The map to modify in place. Must not be nil.
+//	key:      The key to update.
+//	fn:       A function that takes the current value (the zero value if
+//	          absent) and whether it was present, and returns the new value.
+//
+// Returns:
+//
+//	V: The new value stored under key.
+//
+// Panics if inputMap is nil, like any assignment to a nil map.
+func Update[K comparable, V any](inputMap map[K]V, key K, fn func(current V, ok bool) V) V {
+	current, ok := inputMap[key]
+	updated := fn(current, ok)
+	inputMap[key] = updated
+	return updated
+}
+
+// Clone returns a shallow copy of a map. Unlike maps.Clone, it returns an
+// empty, non-nil map for a nil input, in line with the other map utilities.
+func Clone[K comparable, V any](inputMap map[K]V) map[K]V {
+	result := make(map[K]V, len(inputMap))
+	for k, v := range inputMap {
+		result[k] = v
+	}
+	return result
+}
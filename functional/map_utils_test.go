@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/JackovAlltrades/go-generics/functional" // Adjust import path if needed
@@ -215,6 +216,228 @@ func TestMapToSlice(t *testing.T) {
 	})
 }
 
+// --- Test SortedKeys ---
+func TestSortedKeys(t *testing.T) {
+	t.Run("StringKeys", func(t *testing.T) {
+		input := map[string]int{"pear": 1, "apple": 2, "fig": 3}
+		want := []string{"apple", "fig", "pear"}
+		got := functional.SortedKeys(input)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("SortedKeys() = %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("NilMap", func(t *testing.T) {
+		var input map[int]bool
+		got := functional.SortedKeys(input)
+		if got == nil || len(got) != 0 {
+			t.Errorf("SortedKeys() = %#v, want empty non-nil slice", got)
+		}
+	})
+}
+
+// --- Test MapValues / MapKeys ---
+func TestMapValues(t *testing.T) {
+	input := map[string]int{"a": 1, "bb": 2}
+	got := functional.MapValues(input, func(k string, v int) string { return fmt.Sprintf("%s=%d", k, v) })
+	want := map[string]string{"a": "a=1", "bb": "bb=2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapValues() = %#v, want %#v", got, want)
+	}
+
+	empty := functional.MapValues(map[int]int(nil), func(k, v int) int { return v })
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("MapValues(nil) = %#v, want empty non-nil map", empty)
+	}
+}
+
+func TestMapKeys(t *testing.T) {
+	t.Run("NoCollisions", func(t *testing.T) {
+		input := map[int]string{1: "a", 2: "b"}
+		got := functional.MapKeys(input, func(k int, _ string) int { return k * 10 }, nil)
+		want := map[int]string{10: "a", 20: "b"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("MapKeys() = %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("CollisionsResolved", func(t *testing.T) {
+		input := map[string]int{"Apple": 3, "apple": 5, "APPLE": 1, "pear": 2}
+		resolveCalls := 0
+		got := functional.MapKeys(input,
+			func(k string, _ int) string { return strings.ToLower(k) },
+			func(key string, existing, incoming int) int {
+				resolveCalls++
+				return existing + incoming
+			})
+		want := map[string]int{"apple": 9, "pear": 2}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("MapKeys() = %#v, want %#v", got, want)
+		}
+		if resolveCalls != 2 {
+			t.Errorf("resolve called %d times, want 2", resolveCalls)
+		}
+	})
+}
+
+// --- Test FilterMap ---
+func TestFilterMap(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	got := functional.FilterMap(input, func(k string, v int) bool { return v%2 == 0 || k == "a" })
+	want := map[string]int{"a": 1, "b": 2, "d": 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterMap() = %#v, want %#v", got, want)
+	}
+
+	none := functional.FilterMap(input, func(string, int) bool { return false })
+	if none == nil || len(none) != 0 {
+		t.Errorf("FilterMap() keeping nothing = %#v, want empty non-nil map", none)
+	}
+}
+
+// --- Test Invert / InvertMulti ---
+func TestInvert(t *testing.T) {
+	got := functional.Invert(map[string]int{"one": 1, "two": 2})
+	want := map[int]string{1: "one", 2: "two"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Invert() = %#v, want %#v", got, want)
+	}
+
+	collide := functional.Invert(map[string]int{"a": 1, "b": 1})
+	if k := collide[1]; len(collide) != 1 || (k != "a" && k != "b") {
+		t.Errorf("Invert() with shared value = %#v, want one of a/b under 1", collide)
+	}
+
+	if empty := functional.Invert(map[int]int(nil)); empty == nil || len(empty) != 0 {
+		t.Errorf("Invert(nil) = %#v, want empty non-nil map", empty)
+	}
+}
+
+func TestInvertMulti(t *testing.T) {
+	got := functional.InvertMulti(map[string]string{"alice": "eng", "bob": "ops", "carol": "eng"})
+	if len(got) != 2 {
+		t.Fatalf("InvertMulti() = %#v, want 2 groups", got)
+	}
+	compareUnorderedSlices(t, got["eng"], []string{"alice", "carol"})
+	compareUnorderedSlices(t, got["ops"], []string{"bob"})
+}
+
+// --- Test MergeWith ---
+func TestMergeWith(t *testing.T) {
+	a := map[string]int{"x": 1, "y": 2}
+	b := map[string]int{"y": 10, "z": 3}
+	c := map[string]int{"y": 100}
+
+	t.Run("Sum", func(t *testing.T) {
+		got := functional.MergeWith(func(_ string, x, y int) int { return x + y }, a, nil, b, c)
+		want := map[string]int{"x": 1, "y": 112, "z": 3}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("MergeWith() = %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("OrderedResolveSeesEarlierValueFirst", func(t *testing.T) {
+		got := functional.MergeWith(func(_ string, earlier, later int) int { return earlier }, a, b, c)
+		if got["y"] != 2 {
+			t.Errorf("MergeWith() keep-first y = %d, want 2", got["y"])
+		}
+	})
+
+	t.Run("NilResolveLastWins", func(t *testing.T) {
+		got := functional.MergeWith(nil, a, b, c)
+		want := map[string]int{"x": 1, "y": 100, "z": 3}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("MergeWith(nil resolve) = %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("NoMaps", func(t *testing.T) {
+		got := functional.MergeWith[string, int](nil)
+		if got == nil || len(got) != 0 {
+			t.Errorf("MergeWith() without maps = %#v, want empty non-nil map", got)
+		}
+	})
+
+	t.Run("InputsUnchanged", func(t *testing.T) {
+		functional.MergeWith(func(_ string, x, y int) int { return x + y }, a, b)
+		if !reflect.DeepEqual(a, map[string]int{"x": 1, "y": 2}) {
+			t.Errorf("MergeWith() modified its input: %#v", a)
+		}
+	})
+}
+
+// --- Test Pick / Omit ---
+func TestPickOmit(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	if got, want := functional.Pick(input, "a", "c", "missing"), map[string]int{"a": 1, "c": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pick() = %#v, want %#v", got, want)
+	}
+	if got := functional.Pick(input); got == nil || len(got) != 0 {
+		t.Errorf("Pick() without keys = %#v, want empty non-nil map", got)
+	}
+
+	keys := []string{"b", "missing"}
+	if got, want := functional.Omit(input, keys...), map[string]int{"a": 1, "c": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Omit() = %#v, want %#v", got, want)
+	}
+	if len(input) != 3 {
+		t.Errorf("Omit() modified its input: %#v", input)
+	}
+	if got := functional.Omit(map[string]int(nil), "a"); got == nil || len(got) != 0 {
+		t.Errorf("Omit(nil) = %#v, want empty non-nil map", got)
+	}
+}
+
+// --- Test GetOrDefault / Update / Clone ---
+func TestGetOrDefault(t *testing.T) {
+	input := map[string]int{"a": 0}
+	if got := functional.GetOrDefault(input, "a", 7); got != 0 {
+		t.Errorf("GetOrDefault(present zero value) = %d, want 0", got)
+	}
+	if got := functional.GetOrDefault(input, "b", 7); got != 7 {
+		t.Errorf("GetOrDefault(missing) = %d, want 7", got)
+	}
+	if got := functional.GetOrDefault(map[string]int(nil), "a", 7); got != 7 {
+		t.Errorf("GetOrDefault(nil map) = %d, want 7", got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	counts := map[string]int{"a": 1}
+	increment := func(current int, ok bool) int {
+		if !ok {
+			return 100
+		}
+		return current + 1
+	}
+
+	if got := functional.Update(counts, "a", increment); got != 2 {
+		t.Errorf("Update(existing) = %d, want 2", got)
+	}
+	if got := functional.Update(counts, "b", increment); got != 100 {
+		t.Errorf("Update(missing) = %d, want 100", got)
+	}
+	if want := map[string]int{"a": 2, "b": 100}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("map after Update() = %#v, want %#v", counts, want)
+	}
+}
+
+func TestClone(t *testing.T) {
+	input := map[int][]string{1: {"x"}}
+	got := functional.Clone(input)
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf("Clone() = %#v, want %#v", got, input)
+	}
+	got[2] = nil
+	if len(input) != 1 {
+		t.Error("Clone() result shares storage with the input map")
+	}
+	if empty := functional.Clone(map[int]int(nil)); empty == nil {
+		t.Error("Clone(nil) = nil, want empty non-nil map")
+	}
+}
+
 // --- Map Utils Examples ---
 func ExampleKeys() {
 	m := map[string]int{"apple": 1, "banana": 2, "cherry": 3}
@@ -276,6 +499,27 @@ func ExampleMapToSlice() {
 	// Empty Map Result: []
 }
 
+func ExampleMergeWith() {
+	defaults := map[string]int{"timeout": 30, "retries": 3}
+	overrides := map[string]int{"timeout": 10}
+	quotaA := map[string]int{"retries": 2}
+
+	// Later maps win, except that retries take the larger value.
+	merged := functional.MergeWith(func(k string, a, b int) int {
+		if k == "retries" {
+			return max(a, b)
+		}
+		return b
+	}, defaults, overrides, quotaA)
+
+	for _, k := range functional.SortedKeys(merged) {
+		fmt.Printf("%s=%d\n", k, merged[k])
+	}
+	// Output:
+	// retries=3
+	// timeout=10
+}
+
 // --- Benchmarks ---
 
 // Helper to generate maps
@@ -0,0 +1,143 @@
+package functional
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MemoizeOption configures Memoize.
+type MemoizeOption func(*memoizeConfig)
+
+type memoizeConfig struct {
+	maxEntries int
+	safe       bool
+}
+
+// MaxEntries bounds the Memoize cache to n entries. When the cache is full,
+// the least recently used entry is evicted. It panics if n is not positive.
+func MaxEntries(n int) MemoizeOption {
+	if n <= 0 {
+		panic("functional.MaxEntries: n must be positive")
+	}
+	return func(c *memoizeConfig) { c.maxEntries = n }
+}
+
+// ConcurrencySafe makes the memoized function safe to call from multiple
+// goroutines. The wrapped function runs outside the cache lock, so slow calls
+// do not serialize callers and recursive memoized functions do not deadlock;
+// as a consequence, concurrent first calls for the same key may each invoke
+// the wrapped function, and the first result stored wins.
+func ConcurrencySafe() MemoizeOption {
+	return func(c *memoizeConfig) { c.safe = true }
+}
+
+// Memoize returns a function that caches the results of fn by argument. The
+// cache is unbounded unless MaxEntries is given, and is not safe for
+// concurrent use unless ConcurrencySafe is given.
+//
+// Type Parameters:
+//
+//	K: The argument type. Must be comparable, as it is the cache key.
+//	V: The result type.
+//
+// Parameters:
+//
+//	fn: The function to memoize. It should be pure: its result must depend
+//	    only on its argument.
+//	opts: Optional MaxEntries and ConcurrencySafe settings.
+//
+// Returns:
+//
+//	func(K) V: A function with the same results as fn that calls fn at
+//	           most once per cached key.
+func Memoize[K comparable, V any](fn func(K) V, opts ...MemoizeOption) func(K) V {
+	var cfg memoizeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cache := newMemoCache[K, V](cfg.maxEntries)
+
+	if !cfg.safe {
+		return func(k K) V {
+			if v, ok := cache.get(k); ok {
+				return v
+			}
+			return cache.put(k, fn(k))
+		}
+	}
+
+	var mu sync.Mutex
+	return func(k K) V {
+		mu.Lock()
+		v, ok := cache.get(k)
+		mu.Unlock()
+		if ok {
+			return v
+		}
+		v = fn(k)
+		mu.Lock()
+		defer mu.Unlock()
+		return cache.put(k, v)
+	}
+}
+
+// memoCache is a map, optionally bounded with least-recently-used eviction.
+type memoCache[K comparable, V any] struct {
+	maxEntries int
+	values     map[K]V
+	entries    map[K]*list.Element // LRU bookkeeping, used only when bounded
+	order      *list.List          // front is most recently used
+}
+
+func newMemoCache[K comparable, V any](maxEntries int) *memoCache[K, V] {
+	c := &memoCache[K, V]{maxEntries: maxEntries}
+	if maxEntries > 0 {
+		c.entries = make(map[K]*list.Element, maxEntries)
+		c.order = list.New()
+	} else {
+		c.values = make(map[K]V)
+	}
+	return c
+}
+
+type memoEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func (c *memoCache[K, V]) get(k K) (V, bool) {
+	if c.maxEntries == 0 {
+		v, ok := c.values[k]
+		return v, ok
+	}
+	if el, ok := c.entries[k]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*memoEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// put stores v for k unless k is already cached, and returns the cached value.
+// Keeping an existing entry makes concurrent and recursive calls agree on a
+// single result per key.
+func (c *memoCache[K, V]) put(k K, v V) V {
+	if c.maxEntries == 0 {
+		if existing, ok := c.values[k]; ok {
+			return existing
+		}
+		c.values[k] = v
+		return v
+	}
+	if el, ok := c.entries[k]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*memoEntry[K, V]).value
+	}
+	c.entries[k] = c.order.PushFront(&memoEntry[K, V]{key: k, value: v})
+	if c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoEntry[K, V]).key)
+	}
+	return v
+}
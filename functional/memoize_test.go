@@ -0,0 +1,143 @@
+package functional_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+func TestMemoize(t *testing.T) {
+	calls := map[int]int{}
+	square := functional.Memoize(func(n int) int {
+		calls[n]++
+		return n * n
+	})
+
+	for _, n := range []int{2, 3, 2, 2, 3, 4} {
+		if got := square(n); got != n*n {
+			t.Errorf("square(%d) = %d, want %d", n, got, n*n)
+		}
+	}
+	want := map[int]int{2: 1, 3: 1, 4: 1}
+	for n, c := range want {
+		if calls[n] != c {
+			t.Errorf("fn(%d) called %d times, want %d", n, calls[n], c)
+		}
+	}
+}
+
+func TestMemoize_Recursive(t *testing.T) {
+	var fib func(int) uint64
+	calls := 0
+	fib = functional.Memoize(func(n int) uint64 {
+		calls++
+		if n < 2 {
+			return uint64(n)
+		}
+		return fib(n-1) + fib(n-2)
+	}, functional.ConcurrencySafe())
+
+	if got := fib(90); got != 2880067194370816120 {
+		t.Errorf("fib(90) = %d", got)
+	}
+	if calls != 91 {
+		t.Errorf("fn called %d times, want 91", calls)
+	}
+}
+
+func TestMemoize_MaxEntriesEvictsLeastRecentlyUsed(t *testing.T) {
+	var calls []string
+	upper := functional.Memoize(func(s string) string {
+		calls = append(calls, s)
+		return s + "!"
+	}, functional.MaxEntries(2))
+
+	upper("a")
+	upper("b")
+	upper("a") // a is now most recently used
+	upper("c") // evicts b
+	upper("a") // still cached
+	upper("b") // recomputed, evicts c
+	upper("c") // recomputed
+
+	want := []string{"a", "b", "c", "b", "c"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("fn calls = %v, want %v", calls, want)
+	}
+
+	t.Run("NonPositivePanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("MaxEntries(0) did not panic")
+			}
+		}()
+		functional.MaxEntries(0)
+	})
+}
+
+func TestMemoize_ConcurrencySafe(t *testing.T) {
+	var calls atomic.Int64
+	cube := functional.Memoize(func(n int) int {
+		calls.Add(1)
+		return n * n * n
+	}, functional.ConcurrencySafe(), functional.MaxEntries(16))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				n := i % 32
+				if got := cube(n); got != n*n*n {
+					t.Errorf("cube(%d) = %d", n, got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() == 0 {
+		t.Error("wrapped function was never called")
+	}
+}
+
+func ExampleMemoize() {
+	lookups := 0
+	country := functional.Memoize(func(code string) string {
+		lookups++
+		return map[string]string{"de": "Germany", "fr": "France"}[code]
+	}, functional.MaxEntries(100))
+
+	for _, code := range []string{"de", "fr", "de", "de"} {
+		fmt.Println(country(code))
+	}
+	fmt.Println("lookups:", lookups)
+	// Output:
+	// Germany
+	// France
+	// Germany
+	// Germany
+	// lookups: 2
+}
+
+func BenchmarkMemoize_Hit(b *testing.B) {
+	fn := functional.Memoize(func(n int) int { return n * 2 })
+	fn(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn(1)
+	}
+}
+
+func BenchmarkMemoize_HitBoundedSafe(b *testing.B) {
+	fn := functional.Memoize(func(n int) int { return n * 2 }, functional.MaxEntries(128), functional.ConcurrencySafe())
+	fn(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn(1)
+	}
+}
@@ -0,0 +1,181 @@
+package functional
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// PivotTable is a two-dimensional summary produced by Pivot. Row and column
+// headers are sorted in ascending order, and Cells[i][j] holds the aggregate of
+// the elements whose row key is Rows[i] and whose column key is Cols[j].
+type PivotTable[R, C cmp.Ordered, V any] struct {
+	// Label is rendered in the top-left header cell by WriteCSV and Markdown.
+	Label string
+	// Rows holds the distinct row keys in ascending order.
+	Rows []R
+	// Cols holds the distinct column keys in ascending order.
+	Cols []C
+	// Cells holds one aggregate per row/column pair. Pairs that had no
+	// elements hold the zero value of V; use Get to tell them apart.
+	Cells [][]V
+
+	// filled records which cells Pivot left empty. Cells it has no entry
+	// for, as in a table not built by Pivot, count as filled if they exist
+	// in Cells.
+	filled [][]bool
+}
+
+// Pivot builds a pivot table from a slice by grouping its elements on a row key
+// and a column key and aggregating each cell.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	R: The type of the row keys. Must be ordered.
+//	C: The type of the column keys. Must be ordered.
+//	V: The type of the aggregated cell values.
+//
+// Parameters:
+//
+//	input:  The slice to summarize. Can be nil or empty.
+//	rowKey: A function that returns the row key for an element.
+//	colKey: A function that returns the column key for an element.
+//	agg:    A function that reduces the elements of one cell to a value. It is
+//	        only called for cells that have at least one element, with the
+//	        elements in input order.
+//
+// Returns:
+//
+//	*PivotTable[R, C, V]: A new table. For nil or empty input it has no rows
+//	                      or columns.
+func Pivot[T any, R, C cmp.Ordered, V any](
+	input []T, rowKey func(element T) R, colKey func(element T) C, agg func(items []T) V,
+) *PivotTable[R, C, V] {
+	groups := GroupBy2(input, rowKey, colKey)
+
+	colSet := make(map[C]struct{})
+	for _, byCol := range groups {
+		for c := range byCol {
+			colSet[c] = struct{}{}
+		}
+	}
+
+	table := &PivotTable[R, C, V]{
+		Rows: SortedKeys(groups),
+		Cols: SortedKeys(colSet),
+	}
+	table.Cells = make([][]V, len(table.Rows))
+	table.filled = make([][]bool, len(table.Rows))
+	for i, r := range table.Rows {
+		table.Cells[i] = make([]V, len(table.Cols))
+		table.filled[i] = make([]bool, len(table.Cols))
+		for j, c := range table.Cols {
+			if items, ok := groups[r][c]; ok {
+				table.Cells[i][j] = agg(items)
+				table.filled[i][j] = true
+			}
+		}
+	}
+	return table
+}
+
+// Get returns the aggregate for a row/column pair. The boolean is false if the
+// pair is not in the table, is missing from Cells, or no elements fell into
+// that cell.
+func (t *PivotTable[R, C, V]) Get(row R, col C) (V, bool) {
+	var zero V
+	i, foundRow := slices.BinarySearch(t.Rows, row)
+	j, foundCol := slices.BinarySearch(t.Cols, col)
+	if !foundRow || !foundCol || !t.filledAt(i, j) {
+		return zero, false
+	}
+	return t.Cells[i][j], true
+}
+
+// WriteCSV writes the table as CSV: a header record made of Label and the
+// column keys, then one record per row. Empty cells, and cells missing from
+// a short Cells, are written as empty fields. If format is nil, values are
+// formatted with fmt.Sprint.
+func (t *PivotTable[R, C, V]) WriteCSV(w io.Writer, format func(V) string) error {
+	cw := csv.NewWriter(w)
+	for _, record := range t.records(format) {
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Markdown renders the table as a GitHub-flavored Markdown table, using Label
+// as the first header cell. Empty cells are left blank. If format is nil,
+// values are formatted with fmt.Sprint.
+func (t *PivotTable[R, C, V]) Markdown(format func(V) string) string {
+	records := t.records(format)
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for _, cell := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(escape.Replace(cell))
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(records[0])
+	sb.WriteString("|")
+	for range records[0] {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	for _, record := range records[1:] {
+		writeRow(record)
+	}
+	return sb.String()
+}
+
+// filledAt reports whether cell (i, j) holds a value: it must exist in Cells,
+// and must not have been left empty by Pivot.
+func (t *PivotTable[R, C, V]) filledAt(i, j int) bool {
+	if i >= len(t.Cells) || j >= len(t.Cells[i]) {
+		return false
+	}
+	return i >= len(t.filled) || j >= len(t.filled[i]) || t.filled[i][j]
+}
+
+// records lays the table out as rows of strings, header first, for the
+// renderers to share.
+func (t *PivotTable[R, C, V]) records(format func(V) string) [][]string {
+	if format == nil {
+		format = func(v V) string { return fmt.Sprint(v) }
+	}
+
+	records := make([][]string, 0, len(t.Rows)+1)
+	header := make([]string, 0, len(t.Cols)+1)
+	header = append(header, t.Label)
+	for _, c := range t.Cols {
+		header = append(header, fmt.Sprint(c))
+	}
+	records = append(records, header)
+
+	for i, r := range t.Rows {
+		record := make([]string, 0, len(t.Cols)+1)
+		record = append(record, fmt.Sprint(r))
+		for j := range t.Cols {
+			if t.filledAt(i, j) {
+				record = append(record, format(t.Cells[i][j]))
+			} else {
+				record = append(record, "")
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,225 @@
+package functional_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+type pivotSale struct {
+	Region  string
+	Quarter int
+	Units   int
+}
+
+var pivotSales = []pivotSale{
+	{"us", 1, 5},
+	{"eu", 1, 3},
+	{"eu", 2, 4},
+	{"us", 1, 2},
+	{"apac", 3, 9},
+}
+
+func sumUnits(items []pivotSale) int {
+	total := 0
+	for _, s := range items {
+		total += s.Units
+	}
+	return total
+}
+
+func newPivotSalesTable() *functional.PivotTable[string, int, int] {
+	table := functional.Pivot(pivotSales,
+		func(s pivotSale) string { return s.Region },
+		func(s pivotSale) int { return s.Quarter },
+		sumUnits)
+	table.Label = "region"
+	return table
+}
+
+func TestPivot(t *testing.T) {
+	table := newPivotSalesTable()
+
+	if want := []string{"apac", "eu", "us"}; !reflect.DeepEqual(table.Rows, want) {
+		t.Errorf("Pivot() Rows = %v, want %v", table.Rows, want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(table.Cols, want) {
+		t.Errorf("Pivot() Cols = %v, want %v", table.Cols, want)
+	}
+	wantCells := [][]int{
+		{0, 0, 9},
+		{3, 4, 0},
+		{7, 0, 0},
+	}
+	if !reflect.DeepEqual(table.Cells, wantCells) {
+		t.Errorf("Pivot() Cells = %v, want %v", table.Cells, wantCells)
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		if v, ok := table.Get("us", 1); !ok || v != 7 {
+			t.Errorf("Get(us, 1) = %d, %v; want 7, true", v, ok)
+		}
+		if v, ok := table.Get("us", 2); ok {
+			t.Errorf("Get(us, 2) = %d, %v; want empty cell", v, ok)
+		}
+		if _, ok := table.Get("mars", 1); ok {
+			t.Error("Get(mars, 1) reported a value for an unknown row")
+		}
+		if _, ok := table.Get("eu", 9); ok {
+			t.Error("Get(eu, 9) reported a value for an unknown column")
+		}
+	})
+
+	t.Run("EmptyInput", func(t *testing.T) {
+		empty := functional.Pivot([]pivotSale(nil),
+			func(s pivotSale) string { return s.Region },
+			func(s pivotSale) int { return s.Quarter },
+			sumUnits)
+		if len(empty.Rows) != 0 || len(empty.Cols) != 0 || len(empty.Cells) != 0 {
+			t.Errorf("Pivot(nil) = %+v, want empty table", empty)
+		}
+		if got, want := empty.Markdown(nil), "|  |\n| --- |\n"; got != want {
+			t.Errorf("Markdown() of empty table = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestPivotTable_WriteCSV(t *testing.T) {
+	table := newPivotSalesTable()
+
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf, nil); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "region,1,2,3\napac,,,9\neu,3,4,\nus,7,,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	table.Label = "a,b"
+	err := table.WriteCSV(&buf, func(v int) string { return strconv.Itoa(v * 10) })
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want = "\"a,b\",1,2,3\napac,,,90\neu,30,40,\nus,70,,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV() with format =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPivotTable_Markdown(t *testing.T) {
+	table := newPivotSalesTable()
+	table.Label = "region|q"
+
+	want := "| region\\|q | 1 | 2 | 3 |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| apac |  |  | 9 |\n" +
+		"| eu | 3 | 4 |  |\n" +
+		"| us | 7 |  |  |\n"
+	if got := table.Markdown(nil); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPivotTable_NotBuiltByPivot(t *testing.T) {
+	testCases := []struct {
+		name         string
+		table        func() *functional.PivotTable[string, int, int]
+		wantCSV      string
+		wantMarkdown string
+		getRow       string
+		getCol       int
+		wantGet      int
+		wantGetOK    bool
+	}{
+		{
+			name: "Literal",
+			table: func() *functional.PivotTable[string, int, int] {
+				return &functional.PivotTable[string, int, int]{
+					Label: "region", Rows: []string{"eu"}, Cols: []int{1, 2}, Cells: [][]int{{3, 4}},
+				}
+			},
+			wantCSV:      "region,1,2\neu,3,4\n",
+			wantMarkdown: "| region | 1 | 2 |\n| --- | --- | --- |\n| eu | 3 | 4 |\n",
+			getRow:       "eu", getCol: 2, wantGet: 4, wantGetOK: true,
+		},
+		{
+			name: "ShortCells",
+			table: func() *functional.PivotTable[string, int, int] {
+				table := newPivotSalesTable()
+				table.Cells = [][]int{{0, 0}}
+				return table
+			},
+			wantCSV: "region,1,2,3\napac,,,\neu,,,\nus,,,\n",
+			wantMarkdown: "| region | 1 | 2 | 3 |\n| --- | --- | --- | --- |\n" +
+				"| apac |  |  |  |\n| eu |  |  |  |\n| us |  |  |  |\n",
+			getRow: "apac", getCol: 3,
+		},
+		{
+			name: "ExtraRowAndCol",
+			table: func() *functional.PivotTable[string, int, int] {
+				table := newPivotSalesTable()
+				table.Rows = append(table.Rows, "zz")
+				table.Cols = append(table.Cols, 4)
+				table.Cells[0] = append(table.Cells[0], 8)
+				return table
+			},
+			wantCSV: "region,1,2,3,4\napac,,,9,8\neu,3,4,,\nus,7,,,\nzz,,,,\n",
+			wantMarkdown: "| region | 1 | 2 | 3 | 4 |\n| --- | --- | --- | --- | --- |\n" +
+				"| apac |  |  | 9 | 8 |\n| eu | 3 | 4 |  |  |\n| us | 7 |  |  |  |\n| zz |  |  |  |  |\n",
+			getRow: "apac", getCol: 4, wantGet: 8, wantGetOK: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			table := tc.table()
+			var buf bytes.Buffer
+			if err := table.WriteCSV(&buf, nil); err != nil {
+				t.Fatalf("WriteCSV() error = %v", err)
+			}
+			if got := buf.String(); got != tc.wantCSV {
+				t.Errorf("WriteCSV() =\n%s\nwant\n%s", got, tc.wantCSV)
+			}
+			if got := table.Markdown(nil); got != tc.wantMarkdown {
+				t.Errorf("Markdown() =\n%s\nwant\n%s", got, tc.wantMarkdown)
+			}
+			if v, ok := table.Get(tc.getRow, tc.getCol); v != tc.wantGet || ok != tc.wantGetOK {
+				t.Errorf("Get(%s, %d) = %d, %v; want %d, %v", tc.getRow, tc.getCol, v, ok, tc.wantGet, tc.wantGetOK)
+			}
+		})
+	}
+}
+
+func ExamplePivot() {
+	type sale struct {
+		Region  string
+		Product string
+		Units   int
+	}
+	sales := []sale{
+		{"us", "apple", 5},
+		{"eu", "apple", 3},
+		{"eu", "pear", 4},
+		{"us", "apple", 2},
+	}
+
+	table := functional.Pivot(sales,
+		func(s sale) string { return s.Region },
+		func(s sale) string { return s.Product },
+		func(items []sale) int { return len(items) })
+	table.Label = "orders"
+
+	fmt.Print(table.Markdown(nil))
+
+	// Output:
+	// | orders | apple | pear |
+	// | --- | --- | --- |
+	// | eu | 1 | 1 |
+	// | us | 2 |  |
+}
@@ -0,0 +1,109 @@
+package functional
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ReconcileUpdate pairs the current and desired versions of a record whose
+// key is present on both sides but whose contents differ.
+type ReconcileUpdate[T any] struct {
+	Old T // The current version.
+	New T // The desired version.
+}
+
+// ReconcilePlan is the sync plan produced by Reconcile: the records to create,
+// update and delete so that the current list matches the desired one, plus the
+// records that already match.
+type ReconcilePlan[T any] struct {
+	ToCreate  []T                  // Desired records whose key is not current, in desired order.
+	ToUpdate  []ReconcileUpdate[T] // Records on both sides that differ, in desired order.
+	ToDelete  []T                  // Current records whose key is not desired, in current order.
+	Unchanged []T                  // Records on both sides that are equal (current version), in desired order.
+}
+
+// IsEmpty reports whether the plan requires no creates, updates or deletes.
+func (p ReconcilePlan[T]) IsEmpty() bool {
+	return len(p.ToCreate) == 0 && len(p.ToUpdate) == 0 && len(p.ToDelete) == 0
+}
+
+// Reconcile compares a current list of records with a desired list, matching
+// records by key, and returns the plan that brings current in line with
+// desired. Unlike Difference, which compares whole values, it detects records
+// that exist on both sides but were modified.
+//
+// Type Parameters:
+//
+//	T: The type of the records.
+//	K: The type of the record keys (must be comparable).
+//
+// Parameters:
+//
+//	current: The records as they are now. Can be nil or empty.
+//	desired: The records as they should be. Can be nil or empty.
+//	key:     A function that returns the identity of a record, such as its ID.
+//	equal:   Reports whether two records with the same key are the same, that
+//	         is, whether no update is needed.
+//
+// Returns:
+//
+//	ReconcilePlan[T]: The sync plan. All of its slices are non-nil, and each
+//	                  one preserves the order of the input it came from.
+//	error:            If either input holds the same key more than once, an
+//	                  error joining one *DuplicateKeyError[K] per duplicate
+//	                  (wrapped with the side it was found on), and an empty
+//	                  plan, since acting on an ambiguous plan is unsafe. Test
+//	                  for it with errors.Is(err, ErrDuplicateKey).
+//
+// The input slices are never modified.
+func Reconcile[T any, K comparable](
+	current, desired []T, key func(T) K, equal func(a, b T) bool,
+) (ReconcilePlan[T], error) {
+	plan := ReconcilePlan[T]{
+		ToCreate:  make([]T, 0),
+		ToUpdate:  make([]ReconcileUpdate[T], 0),
+		ToDelete:  make([]T, 0),
+		Unchanged: make([]T, 0),
+	}
+
+	currentIndex, currentErrs := indexUniqueKeys(current, key, "current")
+	desiredIndex, desiredErrs := indexUniqueKeys(desired, key, "desired")
+	if err := errors.Join(append(currentErrs, desiredErrs...)...); err != nil {
+		return plan, err
+	}
+
+	for _, want := range desired {
+		i, exists := currentIndex[key(want)]
+		switch {
+		case !exists:
+			plan.ToCreate = append(plan.ToCreate, want)
+		case equal(current[i], want):
+			plan.Unchanged = append(plan.Unchanged, current[i])
+		default:
+			plan.ToUpdate = append(plan.ToUpdate, ReconcileUpdate[T]{Old: current[i], New: want})
+		}
+	}
+	for _, have := range current {
+		if _, exists := desiredIndex[key(have)]; !exists {
+			plan.ToDelete = append(plan.ToDelete, have)
+		}
+	}
+	return plan, nil
+}
+
+// indexUniqueKeys maps each key to the index of its first record and returns
+// one error per repeated key occurrence.
+func indexUniqueKeys[T any, K comparable](input []T, key func(T) K, side string) (map[K]int, []error) {
+	index := make(map[K]int, len(input))
+	var errs []error
+	for i, item := range input {
+		k := key(item)
+		if first, exists := index[k]; exists {
+			errs = append(errs, fmt.Errorf("functional.Reconcile: %s: %w",
+				side, &DuplicateKeyError[K]{Key: k, FirstIndex: first, Index: i}))
+			continue
+		}
+		index[k] = i
+	}
+	return index, errs
+}
@@ -0,0 +1,130 @@
+package functional_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+type syncRecord struct {
+	ID   int
+	Name string
+}
+
+func syncRecordID(r syncRecord) int         { return r.ID }
+func syncRecordsEqual(a, b syncRecord) bool { return a == b }
+
+func TestReconcile(t *testing.T) {
+	current := []syncRecord{{1, "alpha"}, {2, "beta"}, {3, "gamma"}, {4, "delta"}}
+	desired := []syncRecord{{5, "epsilon"}, {3, "GAMMA"}, {1, "alpha"}, {6, "zeta"}, {2, "BETA"}}
+
+	plan, err := functional.Reconcile(current, desired, syncRecordID, syncRecordsEqual)
+	if err != nil {
+		t.Fatalf("Reconcile() unexpected error: %v", err)
+	}
+
+	want := functional.ReconcilePlan[syncRecord]{
+		ToCreate: []syncRecord{{5, "epsilon"}, {6, "zeta"}},
+		ToUpdate: []functional.ReconcileUpdate[syncRecord]{
+			{Old: syncRecord{3, "gamma"}, New: syncRecord{3, "GAMMA"}},
+			{Old: syncRecord{2, "beta"}, New: syncRecord{2, "BETA"}},
+		},
+		ToDelete:  []syncRecord{{4, "delta"}},
+		Unchanged: []syncRecord{{1, "alpha"}},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("Reconcile() =\n%+v\nwant\n%+v", plan, want)
+	}
+	if plan.IsEmpty() {
+		t.Error("IsEmpty() = true for a plan with work to do")
+	}
+}
+
+func TestReconcile_EdgeCases(t *testing.T) {
+	records := []syncRecord{{1, "a"}, {2, "b"}}
+
+	t.Run("InSync", func(t *testing.T) {
+		plan, err := functional.Reconcile(records, records, syncRecordID, syncRecordsEqual)
+		if err != nil || !plan.IsEmpty() || len(plan.Unchanged) != 2 {
+			t.Errorf("Reconcile() of equal lists = %+v, %v; want 2 unchanged and nothing else", plan, err)
+		}
+	})
+
+	t.Run("EmptyCurrent", func(t *testing.T) {
+		plan, err := functional.Reconcile(nil, records, syncRecordID, syncRecordsEqual)
+		if err != nil || !reflect.DeepEqual(plan.ToCreate, records) || len(plan.ToDelete) != 0 {
+			t.Errorf("Reconcile(nil, records) = %+v, %v; want everything created", plan, err)
+		}
+	})
+
+	t.Run("EmptyDesired", func(t *testing.T) {
+		plan, err := functional.Reconcile(records, []syncRecord{}, syncRecordID, syncRecordsEqual)
+		if err != nil || !reflect.DeepEqual(plan.ToDelete, records) || len(plan.ToCreate) != 0 {
+			t.Errorf("Reconcile(records, empty) = %+v, %v; want everything deleted", plan, err)
+		}
+	})
+
+	t.Run("BothNil", func(t *testing.T) {
+		plan, err := functional.Reconcile(nil, nil, syncRecordID, syncRecordsEqual)
+		if err != nil || plan.ToCreate == nil || plan.ToUpdate == nil || plan.ToDelete == nil || plan.Unchanged == nil {
+			t.Errorf("Reconcile(nil, nil) = %#v, %v; want empty non-nil slices", plan, err)
+		}
+	})
+}
+
+func TestReconcile_DuplicateKeys(t *testing.T) {
+	current := []syncRecord{{1, "a"}, {1, "a2"}}
+	desired := []syncRecord{{2, "b"}, {3, "c"}, {2, "b2"}, {2, "b3"}}
+
+	plan, err := functional.Reconcile(current, desired, syncRecordID, syncRecordsEqual)
+	if !errors.Is(err, functional.ErrDuplicateKey) {
+		t.Fatalf("Reconcile() error = %v, want ErrDuplicateKey", err)
+	}
+	if !plan.IsEmpty() || len(plan.Unchanged) != 0 {
+		t.Errorf("Reconcile() with duplicates returned a non-empty plan: %+v", plan)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Reconcile() error %T does not join the individual duplicates", err)
+	}
+	errs := joined.Unwrap()
+	if len(errs) != 3 {
+		t.Fatalf("Reconcile() reported %d duplicates, want 3: %v", len(errs), err)
+	}
+
+	var dup *functional.DuplicateKeyError[int]
+	if !errors.As(errs[2], &dup) || dup.Key != 2 || dup.FirstIndex != 0 || dup.Index != 3 {
+		t.Errorf("third duplicate = %v, want key 2 at index 3 (first at 0)", errs[2])
+	}
+}
+
+func ExampleReconcile() {
+	type user struct {
+		ID    int
+		Email string
+	}
+	inDatabase := []user{{1, "ann@old.example"}, {2, "bob@example.com"}, {3, "cy@example.com"}}
+	fromUpstream := []user{{1, "ann@new.example"}, {2, "bob@example.com"}, {4, "dee@example.com"}}
+
+	plan, err := functional.Reconcile(inDatabase, fromUpstream,
+		func(u user) int { return u.ID },
+		func(a, b user) bool { return a == b })
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	fmt.Println("create:", plan.ToCreate)
+	fmt.Println("update:", plan.ToUpdate)
+	fmt.Println("delete:", plan.ToDelete)
+	fmt.Println("unchanged:", plan.Unchanged)
+	// Output:
+	// create: [{4 dee@example.com}]
+	// update: [{{1 ann@old.example} {1 ann@new.example}}]
+	// delete: [{3 cy@example.com}]
+	// unchanged: [{2 bob@example.com}]
+}
@@ -0,0 +1,413 @@
+package functional
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EditOp is the kind of a single step in an edit script.
+type EditOp int
+
+const (
+	// EditKeep means the element is present in both slices.
+	EditKeep EditOp = iota
+	// EditDelete means the element is only present in the old slice.
+	EditDelete
+	// EditInsert means the element is only present in the new slice.
+	EditInsert
+)
+
+// String returns a short name for the operation.
+func (op EditOp) String() string {
+	switch op {
+	case EditKeep:
+		return "Keep"
+	case EditDelete:
+		return "Delete"
+	case EditInsert:
+		return "Insert"
+	default:
+		return fmt.Sprintf("EditOp(%d)", int(op))
+	}
+}
+
+// Edit is one step of an edit script produced by DiffSlices. OldIndex and
+// NewIndex locate Value in the old and new slices; OldIndex is -1 for inserts
+// and NewIndex is -1 for deletes.
+type Edit[T any] struct {
+	Op       EditOp
+	OldIndex int
+	NewIndex int
+	Value    T
+}
+
+// DiffSlices computes a shortest edit script that turns oldSlice into newSlice,
+// using Myers' O(ND) difference algorithm, where D is the number of inserted
+// and deleted elements, in its linear-space form: memory use is O(N+M) beyond
+// the returned script, even when the slices are mostly different.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slices, must be comparable.
+//
+// Parameters:
+//
+//	oldSlice: The original sequence. Can be nil or empty.
+//	newSlice: The target sequence. Can be nil or empty.
+//
+// Returns:
+//
+//	[]Edit[T]: One Edit per element of either slice, in sequence order. Keeping
+//	           every EditKeep and EditInsert value yields newSlice; keeping
+//	           every EditKeep and EditDelete value yields oldSlice. Within a
+//	           block of changes, deletions come before insertions. Returns an
+//	           empty slice if both inputs are nil or empty.
+//
+// The input slices are never modified.
+func DiffSlices[T comparable](oldSlice, newSlice []T) []Edit[T] {
+	return DiffSlicesFunc(oldSlice, newSlice, func(a, b T) bool { return a == b })
+}
+
+// DiffSlicesFunc computes a shortest edit script like DiffSlices, using an
+// equality callback for elements that are not comparable or that need a
+// looser notion of equality (for example, ignoring whitespace).
+func DiffSlicesFunc[T any](oldSlice, newSlice []T, equal func(a, b T) bool) []Edit[T] {
+	edits := make([]Edit[T], 0, max(len(oldSlice), len(newSlice)))
+
+	// Common prefixes and suffixes never need the search; trimming them keeps
+	// the Myers search small for the typical "few lines changed" case.
+	prefix := 0
+	for prefix < len(oldSlice) && prefix < len(newSlice) && equal(oldSlice[prefix], newSlice[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldSlice)-prefix && suffix < len(newSlice)-prefix &&
+		equal(oldSlice[len(oldSlice)-1-suffix], newSlice[len(newSlice)-1-suffix]) {
+		suffix++
+	}
+
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit[T]{Op: EditKeep, OldIndex: i, NewIndex: i, Value: oldSlice[i]})
+	}
+	middleOld := oldSlice[prefix : len(oldSlice)-suffix]
+	middleNew := newSlice[prefix : len(newSlice)-suffix]
+	for _, e := range myersDiff(middleOld, middleNew, equal) {
+		if e.OldIndex >= 0 {
+			e.OldIndex += prefix
+		}
+		if e.NewIndex >= 0 {
+			e.NewIndex += prefix
+		}
+		edits = append(edits, e)
+	}
+	for i := 0; i < suffix; i++ {
+		oldIndex := len(oldSlice) - suffix + i
+		newIndex := len(newSlice) - suffix + i
+		edits = append(edits, Edit[T]{Op: EditKeep, OldIndex: oldIndex, NewIndex: newIndex, Value: oldSlice[oldIndex]})
+	}
+	return edits
+}
+
+// myersDiff computes a shortest edit script with the linear-space variant of
+// Myers' algorithm: it finds the middle snake of an optimal path by searching
+// from both ends at once, then recurses on the parts before and after it. This
+// needs O(N+M) memory on top of the result, however large D grows.
+func myersDiff[T any](a, b []T, equal func(x, y T) bool) []Edit[T] {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	size := 2*(len(a)+len(b)) + 3
+	d := &myersDiffer[T]{
+		a: a, b: b, equal: equal,
+		forward:  make([]int, size),
+		backward: make([]int, size),
+		edits:    make([]Edit[T], 0, len(a)+len(b)),
+	}
+	d.compare(0, len(a), 0, len(b))
+	deletionsFirst(d.edits)
+	return d.edits
+}
+
+// myersDiffer holds the inputs and the scratch space shared by the recursive
+// steps of myersDiff.
+type myersDiffer[T any] struct {
+	a, b              []T
+	equal             func(x, y T) bool
+	forward, backward []int // Furthest reaching x per diagonal, offset by len/2.
+	edits             []Edit[T]
+}
+
+// compare appends the edit script for a[aLo:aHi] against b[bLo:bHi].
+func (d *myersDiffer[T]) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.equal(d.a[aLo], d.b[bLo]) {
+		d.keep(aLo, bLo)
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.equal(d.a[aHi-1-suffix], d.b[bHi-1-suffix]) {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			d.edits = append(d.edits, Edit[T]{Op: EditInsert, OldIndex: -1, NewIndex: y, Value: d.b[y]})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			d.edits = append(d.edits, Edit[T]{Op: EditDelete, OldIndex: x, NewIndex: -1, Value: d.a[x]})
+		}
+	default:
+		// With common ends trimmed and both sides non-empty, D >= 2, so both
+		// halves around the middle snake are strictly smaller.
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.keep(x, y)
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.keep(aHi+i, bHi+i)
+	}
+}
+
+func (d *myersDiffer[T]) keep(x, y int) {
+	d.edits = append(d.edits, Edit[T]{Op: EditKeep, OldIndex: x, NewIndex: y, Value: d.a[x]})
+}
+
+// middleSnake returns the diagonal run from (x, y) to (u, v), in absolute
+// indices, that lies in the middle of a shortest edit path through
+// a[aLo:aHi] and b[bLo:bHi]. The forward search works on diagonals k = x - y
+// from the start; the backward search on diagonals of the reversed sequences,
+// where diagonal k meets forward diagonal delta - k.
+func (d *myersDiffer[T]) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	offset := len(d.forward) / 2
+	fw, bw := d.forward, d.backward
+	fw[offset+1], bw[offset+1] = 0, 0
+
+	for D := 0; D <= (n+m+1)/2; D++ {
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && fw[offset+k-1] < fw[offset+k+1]) {
+				x = fw[offset+k+1]
+			} else {
+				x = fw[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.equal(d.a[aLo+x], d.b[bLo+y]) {
+				x++
+				y++
+			}
+			fw[offset+k] = x
+			if r := delta - k; odd && r >= -(D-1) && r <= D-1 && x+bw[offset+r] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y
+			}
+		}
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && bw[offset+k-1] < bw[offset+k+1]) {
+				x = bw[offset+k+1]
+			} else {
+				x = bw[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.equal(d.a[aHi-1-x], d.b[bHi-1-y]) {
+				x++
+				y++
+			}
+			bw[offset+k] = x
+			if r := delta - k; !odd && r >= -D && r <= D && x+fw[offset+r] >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - y0
+			}
+		}
+	}
+	panic("functional.DiffSlices: middle snake not found")
+}
+
+// deletionsFirst reorders every run of changes in place so that its deletions
+// come before its insertions, keeping the order within each kind.
+func deletionsFirst[T any](edits []Edit[T]) {
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditKeep {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != EditKeep {
+			j++
+		}
+		slices.SortStableFunc(edits[i:j], func(x, y Edit[T]) int {
+			return int(x.Op) - int(y.Op) // EditDelete < EditInsert.
+		})
+		i = j
+	}
+}
+
+// LCS returns a longest common subsequence of two slices: the longest sequence
+// of elements that appears in both, in the same relative order but not
+// necessarily contiguously. When several exist, the one found by DiffSlices is
+// returned.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slices, must be comparable.
+//
+// Parameters:
+//
+//	a: The first slice. Can be nil or empty.
+//	b: The second slice. Can be nil or empty.
+//
+// Returns:
+//
+//	[]T: A new slice holding the common subsequence. Returns an empty slice if
+//	     the inputs share no elements.
+func LCS[T comparable](a, b []T) []T {
+	result := make([]T, 0)
+	for _, e := range DiffSlices(a, b) {
+		if e.Op == EditKeep {
+			result = append(result, e.Value)
+		}
+	}
+	return result
+}
+
+// EditDistance returns the minimum number of insertions and deletions needed
+// to turn a into b, which is the number of non-Keep steps in the edit script
+// from DiffSlices, or len(a) + len(b) - 2*len(LCS(a, b)). Substitutions are
+// not a separate operation, so replacing one element counts as two edits
+// (unlike the Levenshtein distance).
+func EditDistance[T comparable](a, b []T) int {
+	distance := 0
+	for _, e := range DiffSlices(a, b) {
+		if e.Op != EditKeep {
+			distance++
+		}
+	}
+	return distance
+}
+
+// UnifiedDiff renders the differences between two slices of lines in the
+// unified diff format used by diff -u and git diff.
+//
+// Parameters:
+//
+//	oldName: The name shown on the "---" header line.
+//	newName: The name shown on the "+++" header line.
+//	oldLines, newLines: The lines to compare, without trailing newlines.
+//	context: The number of unchanged lines shown around each change. Negative
+//	         values are treated as 0.
+//
+// Returns:
+//
+//	string: The rendered diff with one hunk per group of nearby changes, each
+//	        line terminated by a newline. Returns "" if the inputs are equal.
+func UnifiedDiff(oldName, newName string, oldLines, newLines []string, context int) string {
+	context = max(context, 0)
+	edits := DiffSlices(oldLines, newLines)
+
+	var sb strings.Builder
+	for _, h := range unifiedHunks(edits, context) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			unifiedRange(h.oldStart, h.oldCount), unifiedRange(h.newStart, h.newCount))
+		for _, e := range edits[h.first:h.last] {
+			switch e.Op {
+			case EditKeep:
+				sb.WriteString(" ")
+			case EditDelete:
+				sb.WriteString("-")
+			case EditInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(e.Value)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// unifiedHunk is a range of edits [first, last) rendered as one hunk, with the
+// 0-based start positions and line counts for its header.
+type unifiedHunk struct {
+	first, last        int
+	oldStart, newStart int
+	oldCount, newCount int
+}
+
+// unifiedHunks groups the changes of an edit script into hunks with the given
+// amount of context, merging hunks whose context would overlap or touch.
+func unifiedHunks[T any](edits []Edit[T], context int) []unifiedHunk {
+	var hunks []unifiedHunk
+	cursor, oldPos, newPos := 0, 0, 0 // Elements of each side before edits[cursor].
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditKeep {
+			i++
+			continue
+		}
+
+		// Extend the hunk while the next change is within 2*context keeps.
+		first := max(i-context, 0)
+		last := i
+		for last < len(edits) {
+			next := last
+			for next < len(edits) && edits[next].Op == EditKeep {
+				next++
+			}
+			if next == len(edits) || next-last > 2*context {
+				last = min(last+context, len(edits))
+				break
+			}
+			last = next
+			for last < len(edits) && edits[last].Op != EditKeep {
+				last++
+			}
+		}
+
+		for ; cursor < first; cursor++ {
+			oldPos, newPos = advancePositions(edits[cursor].Op, oldPos, newPos)
+		}
+		h := unifiedHunk{first: first, last: last, oldStart: oldPos, newStart: newPos}
+		for _, e := range edits[first:last] {
+			h.oldCount, h.newCount = advancePositions(e.Op, h.oldCount, h.newCount)
+		}
+		hunks = append(hunks, h)
+		i = last
+	}
+	return hunks
+}
+
+// advancePositions counts one edit against the old and new sides it consumes.
+func advancePositions(op EditOp, oldPos, newPos int) (int, int) {
+	if op != EditInsert {
+		oldPos++
+	}
+	if op != EditDelete {
+		newPos++
+	}
+	return oldPos, newPos
+}
+
+// unifiedRange formats a hunk range the way GNU diff does: a single line is
+// written as its 1-based number, and an empty range refers to the line before it.
+func unifiedRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
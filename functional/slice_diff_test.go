@@ -0,0 +1,266 @@
+package functional_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+// applyEdits rebuilds the old and new sides from an edit script, checking the
+// recorded indices along the way.
+func applyEdits[T any](t *testing.T, edits []functional.Edit[T]) (oldSide, newSide []T) {
+	t.Helper()
+	oldSide, newSide = []T{}, []T{}
+	for _, e := range edits {
+		switch e.Op {
+		case functional.EditKeep:
+			if e.OldIndex != len(oldSide) || e.NewIndex != len(newSide) {
+				t.Fatalf("Keep edit %+v has wrong indices (old %d, new %d)", e, len(oldSide), len(newSide))
+			}
+			oldSide = append(oldSide, e.Value)
+			newSide = append(newSide, e.Value)
+		case functional.EditDelete:
+			if e.OldIndex != len(oldSide) || e.NewIndex != -1 {
+				t.Fatalf("Delete edit %+v has wrong indices (old %d)", e, len(oldSide))
+			}
+			oldSide = append(oldSide, e.Value)
+		case functional.EditInsert:
+			if e.NewIndex != len(newSide) || e.OldIndex != -1 {
+				t.Fatalf("Insert edit %+v has wrong indices (new %d)", e, len(newSide))
+			}
+			newSide = append(newSide, e.Value)
+		}
+	}
+	return oldSide, newSide
+}
+
+// lcsLengthDP is a reference O(n*m) LCS length used to check minimality.
+func lcsLengthDP[T comparable](a, b []T) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else {
+				curr[j] = max(prev[j], curr[j-1])
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func TestDiffSlices(t *testing.T) {
+	testCases := []struct {
+		name         string
+		oldSlice     []string
+		newSlice     []string
+		wantDistance int
+	}{
+		{"Identical", strings.Split("abc", ""), strings.Split("abc", ""), 0},
+		{"BothEmpty", nil, []string{}, 0},
+		{"AllInserted", nil, strings.Split("abc", ""), 3},
+		{"AllDeleted", strings.Split("abc", ""), nil, 3},
+		{"Classic", strings.Split("ABCABBA", ""), strings.Split("CBABAC", ""), 5},
+		{"Replace", strings.Split("abc", ""), strings.Split("axc", ""), 2},
+		{"PrefixAndSuffix", strings.Split("xxabyy", ""), strings.Split("xxbayy", ""), 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits := functional.DiffSlices(tc.oldSlice, tc.newSlice)
+			oldSide, newSide := applyEdits(t, edits)
+			if len(oldSide) != len(tc.oldSlice) || (len(oldSide) > 0 && !reflect.DeepEqual(oldSide, tc.oldSlice)) {
+				t.Errorf("old side = %v, want %v", oldSide, tc.oldSlice)
+			}
+			if len(newSide) != len(tc.newSlice) || (len(newSide) > 0 && !reflect.DeepEqual(newSide, tc.newSlice)) {
+				t.Errorf("new side = %v, want %v", newSide, tc.newSlice)
+			}
+			if got := functional.EditDistance(tc.oldSlice, tc.newSlice); got != tc.wantDistance {
+				t.Errorf("EditDistance() = %d, want %d", got, tc.wantDistance)
+			}
+		})
+	}
+
+	t.Run("DeletesBeforeInserts", func(t *testing.T) {
+		edits := functional.DiffSlices([]int{1, 2, 3}, []int{1, 9, 3})
+		ops := functional.Map(edits, func(e functional.Edit[int]) functional.EditOp { return e.Op })
+		want := []functional.EditOp{functional.EditKeep, functional.EditDelete, functional.EditInsert, functional.EditKeep}
+		if !reflect.DeepEqual(ops, want) {
+			t.Errorf("ops = %v, want %v", ops, want)
+		}
+	})
+}
+
+func TestDiffSlices_RandomMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	randomSlice := func() []int {
+		s := make([]int, rng.Intn(40))
+		for i := range s {
+			s[i] = rng.Intn(4)
+		}
+		return s
+	}
+
+	for i := 0; i < 300; i++ {
+		a, b := randomSlice(), randomSlice()
+		edits := functional.DiffSlices(a, b)
+		oldSide, newSide := applyEdits(t, edits)
+		if len(oldSide) != len(a) || len(newSide) != len(b) ||
+			(len(a) > 0 && !reflect.DeepEqual(oldSide, a)) || (len(b) > 0 && !reflect.DeepEqual(newSide, b)) {
+			t.Fatalf("edit script for %v -> %v does not reproduce the inputs", a, b)
+		}
+		wantDistance := len(a) + len(b) - 2*lcsLengthDP(a, b)
+		if got := functional.EditDistance(a, b); got != wantDistance {
+			t.Fatalf("EditDistance(%v, %v) = %d, want %d", a, b, got, wantDistance)
+		}
+		if got := len(functional.LCS(a, b)); got != lcsLengthDP(a, b) {
+			t.Fatalf("len(LCS(%v, %v)) = %d, want %d", a, b, got, lcsLengthDP(a, b))
+		}
+		for j := 1; j < len(edits); j++ {
+			if edits[j-1].Op == functional.EditInsert && edits[j].Op == functional.EditDelete {
+				t.Fatalf("edit script for %v -> %v has an insertion before a deletion at %d", a, b, j)
+			}
+		}
+	}
+}
+
+func TestDiffSlices_LargeDLinearMemory(t *testing.T) {
+	// Every other element differs, so D = 2n and a search that kept each
+	// step's diagonals would allocate O(n²) ints (tens of megabytes here).
+	const n = 2000
+	oldSlice, newSlice := make([]int, 2*n), make([]int, 2*n)
+	for i := range oldSlice {
+		oldSlice[i], newSlice[i] = i, i
+		if i%2 == 1 {
+			newSlice[i] = -i
+		}
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	edits := functional.DiffSlices(oldSlice, newSlice)
+	runtime.ReadMemStats(&after)
+
+	oldSide, newSide := applyEdits(t, edits)
+	if !reflect.DeepEqual(oldSide, oldSlice) || !reflect.DeepEqual(newSide, newSlice) {
+		t.Fatal("edit script does not reproduce the inputs")
+	}
+	if got := len(edits); got != 3*n {
+		t.Errorf("len(edits) = %d, want %d", got, 3*n)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 4<<20 {
+		t.Errorf("DiffSlices allocated %d bytes, want at most %d", allocated, 4<<20)
+	}
+}
+
+func TestDiffSlicesFunc(t *testing.T) {
+	oldLines := []string{"a ", "B", "c"}
+	newLines := []string{"a", "b", "d"}
+	edits := functional.DiffSlicesFunc(oldLines, newLines, func(x, y string) bool {
+		return strings.EqualFold(strings.TrimSpace(x), strings.TrimSpace(y))
+	})
+	if got := functional.Filter(edits, func(e functional.Edit[string]) bool { return e.Op == functional.EditKeep }); len(got) != 2 {
+		t.Errorf("DiffSlicesFunc() kept %d elements, want 2: %+v", len(got), edits)
+	}
+}
+
+func TestLCS(t *testing.T) {
+	if got := functional.LCS([]rune("AGGTAB"), []rune("GXTXAYB")); string(got) != "GTAB" {
+		t.Errorf("LCS() = %q, want %q", string(got), "GTAB")
+	}
+	if got := functional.LCS([]int{1, 2}, []int{3, 4}); got == nil || len(got) != 0 {
+		t.Errorf("LCS() with nothing in common = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestEditOp_String(t *testing.T) {
+	for op, want := range map[functional.EditOp]string{
+		functional.EditKeep:   "Keep",
+		functional.EditDelete: "Delete",
+		functional.EditInsert: "Insert",
+		functional.EditOp(9):  "EditOp(9)",
+	} {
+		if got := op.String(); got != want {
+			t.Errorf("EditOp(%d).String() = %q, want %q", int(op), got, want)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldLines := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+	newLines := []string{"1", "two", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"}
+
+	t.Run("SeparateHunks", func(t *testing.T) {
+		want := "--- a.txt\n+++ b.txt\n" +
+			"@@ -1,3 +1,3 @@\n 1\n-2\n+two\n 3\n" +
+			"@@ -12 +12,2 @@\n 12\n+13\n"
+		if got := functional.UnifiedDiff("a.txt", "b.txt", oldLines, newLines, 1); got != want {
+			t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("MergedHunk", func(t *testing.T) {
+		got := functional.UnifiedDiff("a", "b", oldLines, newLines, 5)
+		if strings.Count(got, "@@ -") != 1 {
+			t.Errorf("UnifiedDiff() with wide context should have one hunk:\n%s", got)
+		}
+		if !strings.Contains(got, "@@ -1,12 +1,13 @@\n") {
+			t.Errorf("UnifiedDiff() merged header wrong:\n%s", got)
+		}
+	})
+
+	t.Run("ZeroContext", func(t *testing.T) {
+		want := "--- a\n+++ b\n@@ -2 +2 @@\n-2\n+two\n@@ -12,0 +13 @@\n+13\n"
+		if got := functional.UnifiedDiff("a", "b", oldLines, newLines, 0); got != want {
+			t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("FromEmpty", func(t *testing.T) {
+		want := "--- /dev/null\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+		if got := functional.UnifiedDiff("/dev/null", "new", nil, []string{"x", "y"}, 3); got != want {
+			t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		if got := functional.UnifiedDiff("a", "b", oldLines, oldLines, 3); got != "" {
+			t.Errorf("UnifiedDiff() of equal inputs = %q, want empty", got)
+		}
+	})
+}
+
+func ExampleUnifiedDiff() {
+	running := []string{"replicas: 2", "image: app:1.4", "port: 8080"}
+	declared := []string{"replicas: 3", "image: app:1.4", "port: 8080"}
+
+	fmt.Print(functional.UnifiedDiff("running", "declared", running, declared, 1))
+	// Output:
+	// --- running
+	// +++ declared
+	// @@ -1,2 +1,2 @@
+	// -replicas: 2
+	// +replicas: 3
+	//  image: app:1.4
+}
+
+func BenchmarkDiffSlices_N1000_FewChanges(b *testing.B) {
+	oldSlice := make([]int, 1000)
+	for i := range oldSlice {
+		oldSlice[i] = i
+	}
+	newSlice := append([]int(nil), oldSlice...)
+	for i := 0; i < len(newSlice); i += 100 {
+		newSlice[i] = -i
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = functional.DiffSlices(oldSlice, newSlice)
+	}
+}
@@ -0,0 +1,186 @@
+package functional
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+)
+
+// SortBy sorts a slice in place in ascending order of an ordered key.
+// The sort is not guaranteed to be stable; use SortStableBy to keep the input
+// order of elements with equal keys.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//	K: The type of the sort key. Must be ordered.
+//
+// Parameters:
+//
+//	slice: The slice to sort in place. Nil and empty slices are left as is.
+//	key:   A function that extracts the sort key from an element. It is
+//	       called O(n log n) times, so it should be cheap.
+//
+// Returns:
+//
+//	None. The input slice itself is modified.
+func SortBy[T any, K cmp.Ordered](slice []T, key func(T) K) {
+	slices.SortFunc(slice, Comparing(key))
+}
+
+// SortStableBy sorts a slice in place in ascending order of an ordered key,
+// keeping the original order of elements with equal keys. To sort by several
+// fields, pass a Comparator built with Comparing and ThenComparing to
+// slices.SortStableFunc instead.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//	K: The type of the sort key. Must be ordered.
+//
+// Parameters:
+//
+//	slice: The slice to sort in place. Nil and empty slices are left as is.
+//	key:   A function that extracts the sort key from an element.
+//
+// Returns:
+//
+//	None. The input slice itself is modified.
+func SortStableBy[T any, K cmp.Ordered](slice []T, key func(T) K) {
+	slices.SortStableFunc(slice, Comparing(key))
+}
+
+// IsSortedBy reports whether a slice is sorted in ascending order of an
+// ordered key. Nil and empty slices are sorted.
+func IsSortedBy[T any, K cmp.Ordered](slice []T, key func(T) K) bool {
+	return slices.IsSortedFunc(slice, Comparing(key))
+}
+
+// MinBy returns a pointer to the smallest element of a slice according to a
+// Comparator. If several elements are minimal, the first one is returned.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	slice: The slice to search. Can be nil or empty.
+//	c:     The Comparator that orders the elements.
+//
+// Returns:
+//
+//	*T:   A pointer to the minimal element in the original slice, or nil.
+//	bool: false if the slice is nil or empty.
+func MinBy[T any](slice []T, c Comparator[T]) (*T, bool) {
+	if len(slice) == 0 {
+		return nil, false
+	}
+	best := 0
+	for i := 1; i < len(slice); i++ {
+		if c(slice[i], slice[best]) < 0 {
+			best = i
+		}
+	}
+	return &slice[best], true
+}
+
+// MaxBy returns a pointer to the largest element of a slice according to a
+// Comparator. If several elements are maximal, the first one is returned.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	slice: The slice to search. Can be nil or empty.
+//	c:     The Comparator that orders the elements.
+//
+// Returns:
+//
+//	*T:   A pointer to the maximal element in the original slice, or nil.
+//	bool: false if the slice is nil or empty.
+func MaxBy[T any](slice []T, c Comparator[T]) (*T, bool) {
+	if len(slice) == 0 {
+		return nil, false
+	}
+	best := 0
+	for i := 1; i < len(slice); i++ {
+		if c(slice[i], slice[best]) > 0 {
+			best = i
+		}
+	}
+	return &slice[best], true
+}
+
+// TopKBy returns the k largest elements of a slice according to a Comparator,
+// largest first. It keeps a bounded min-heap of size k, so it runs in
+// O(n log k) time and O(k) extra space instead of sorting the whole input.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice.
+//
+// Parameters:
+//
+//	slice: The slice to select from. Can be nil or empty. It is not modified.
+//	k:     The number of elements to return. Must not be negative.
+//	c:     The Comparator that orders the elements. Use c.Reversed() to get the
+//	       k smallest elements instead.
+//
+// Returns:
+//
+//	[]T: A new slice with min(k, len(slice)) elements in descending order.
+//	     Among elements that compare equal, which ones are kept is unspecified.
+//	     Returns an empty slice if k is 0 or the input is nil or empty.
+//
+// Panics if k is negative.
+func TopKBy[T any](slice []T, k int, c Comparator[T]) []T {
+	if k < 0 {
+		panic("functional.TopKBy: k must not be negative")
+	}
+	k = min(k, len(slice))
+	if k == 0 {
+		return []T{}
+	}
+
+	h := &boundedHeap[T]{items: make([]T, 0, k), less: func(a, b T) bool { return c(a, b) < 0 }}
+	for _, item := range slice {
+		if h.Len() < k {
+			heap.Push(h, item)
+		} else if c(item, h.items[0]) > 0 {
+			// Replace the smallest retained element.
+			h.items[0] = item
+			heap.Fix(h, 0)
+		}
+	}
+
+	// Popping yields ascending order; fill the result from the back.
+	result := make([]T, k)
+	for i := k - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(T)
+	}
+	return result
+}
+
+// boundedHeap is a container/heap implementation ordered by less, used by
+// TopKBy to retain the largest elements seen so far.
+type boundedHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *boundedHeap[T]) Len() int           { return len(h.items) }
+func (h *boundedHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *boundedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *boundedHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *boundedHeap[T]) Pop() any {
+	last := len(h.items) - 1
+	item := h.items[last]
+	var zero T
+	h.items[last] = zero // Drop the reference for the garbage collector.
+	h.items = h.items[:last]
+	return item
+}
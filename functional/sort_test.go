@@ -0,0 +1,166 @@
+package functional_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+func TestSortBy(t *testing.T) {
+	input := []person{{"Cleo", 40}, {"Abe", 25}, {"Bo", 33}}
+	functional.SortBy(input, func(p person) int { return p.Age })
+	want := []person{{"Abe", 25}, {"Bo", 33}, {"Cleo", 40}}
+	if !reflect.DeepEqual(input, want) {
+		t.Errorf("SortBy() = %v, want %v", input, want)
+	}
+
+	var empty []person
+	functional.SortBy(empty, func(p person) string { return p.Name }) // Must not panic.
+}
+
+func TestSortStableBy(t *testing.T) {
+	input := []person{{"a", 2}, {"b", 1}, {"c", 2}, {"d", 1}, {"e", 2}}
+	functional.SortStableBy(input, func(p person) int { return p.Age })
+	want := []person{{"b", 1}, {"d", 1}, {"a", 2}, {"c", 2}, {"e", 2}}
+	if !reflect.DeepEqual(input, want) {
+		t.Errorf("SortStableBy() = %v, want %v", input, want)
+	}
+}
+
+func TestIsSortedBy(t *testing.T) {
+	byLen := func(s string) int { return len(s) }
+	if !functional.IsSortedBy([]string{"a", "bb", "cc", "ddd"}, byLen) {
+		t.Error("IsSortedBy() = false for sorted input")
+	}
+	if functional.IsSortedBy([]string{"bb", "a"}, byLen) {
+		t.Error("IsSortedBy() = true for unsorted input")
+	}
+	if !functional.IsSortedBy([]string(nil), byLen) {
+		t.Error("IsSortedBy(nil) = false, want true")
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	byAge := functional.Comparing(func(p person) int { return p.Age })
+	input := []person{{"a", 30}, {"b", 20}, {"c", 40}, {"d", 20}, {"e", 40}}
+
+	minP, ok := functional.MinBy(input, byAge)
+	if !ok || minP.Name != "b" {
+		t.Errorf("MinBy() = %v, %v; want first minimal element b", minP, ok)
+	}
+	if minP != &input[1] {
+		t.Error("MinBy() should return a pointer into the input slice")
+	}
+
+	maxP, ok := functional.MaxBy(input, byAge)
+	if !ok || maxP.Name != "c" {
+		t.Errorf("MaxBy() = %v, %v; want first maximal element c", maxP, ok)
+	}
+
+	if p, ok := functional.MinBy([]person(nil), byAge); ok || p != nil {
+		t.Errorf("MinBy(nil) = %v, %v; want nil, false", p, ok)
+	}
+	if p, ok := functional.MaxBy([]person{}, byAge); ok || p != nil {
+		t.Errorf("MaxBy(empty) = %v, %v; want nil, false", p, ok)
+	}
+}
+
+func TestTopKBy(t *testing.T) {
+	byValue := functional.Comparing(func(n int) int { return n })
+	input := []int{5, 1, 9, 3, 7, 9, 2}
+
+	testCases := []struct {
+		name string
+		k    int
+		c    functional.Comparator[int]
+		want []int
+	}{
+		{"Top3", 3, byValue, []int{9, 9, 7}},
+		{"Bottom2", 2, byValue.Reversed(), []int{1, 2}},
+		{"KLargerThanInput", 10, byValue, []int{9, 9, 7, 5, 3, 2, 1}},
+		{"KZero", 0, byValue, []int{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := functional.TopKBy(input, tc.k, tc.c); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("TopKBy(k=%d) = %v, want %v", tc.k, got, tc.want)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(input, []int{5, 1, 9, 3, 7, 9, 2}) {
+		t.Errorf("TopKBy() modified its input: %v", input)
+	}
+
+	t.Run("MatchesFullSort", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		data := make([]int, 500)
+		for i := range data {
+			data[i] = rng.Intn(1000)
+		}
+		sorted := slices.Clone(data)
+		slices.SortFunc(sorted, byValue.Reversed())
+		if got := functional.TopKBy(data, 25, byValue); !reflect.DeepEqual(got, sorted[:25]) {
+			t.Errorf("TopKBy() = %v, want %v", got, sorted[:25])
+		}
+	})
+
+	t.Run("NegativeKPanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("TopKBy() with negative k did not panic")
+			}
+		}()
+		functional.TopKBy(input, -1, byValue)
+	})
+}
+
+func ExampleTopKBy() {
+	type page struct {
+		Path  string
+		Views int
+	}
+	pages := []page{{"/", 900}, {"/docs", 450}, {"/blog", 1200}, {"/about", 80}}
+
+	top := functional.TopKBy(pages, 2, functional.Comparing(func(p page) int { return p.Views }))
+	fmt.Println(top)
+	// Output:
+	// [{/blog 1200} {/ 900}]
+}
+
+// --- Benchmarks ---
+
+var topKBenchData = func() []int {
+	rng := rand.New(rand.NewSource(42))
+	data := make([]int, 100000)
+	for i := range data {
+		data[i] = rng.Int()
+	}
+	return data
+}()
+
+func BenchmarkTopKBy_Heap_N100000_K10(b *testing.B) {
+	byValue := functional.Comparing(func(n int) int { return n })
+	b.ResetTimer()
+	var result []int
+	for i := 0; i < b.N; i++ {
+		result = functional.TopKBy(topKBenchData, 10, byValue)
+	}
+	_ = result
+}
+
+func BenchmarkTopKBy_SortThenSlice_N100000_K10(b *testing.B) {
+	b.ResetTimer()
+	var result []int
+	for i := 0; i < b.N; i++ {
+		sorted := slices.Clone(topKBenchData)
+		slices.Sort(sorted)
+		slices.Reverse(sorted)
+		result = sorted[:10]
+	}
+	_ = result
+}
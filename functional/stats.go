@@ -0,0 +1,661 @@
+package functional
+
+import (
+	"errors"
+	"iter"
+	"math"
+	"slices"
+)
+
+// Overflow and NaN policy for the statistics functions:
+//
+//   - Sum, SumOf and Product use ordinary Go arithmetic, so integer results
+//     wrap around on overflow. Use SumChecked or ProductChecked when wrapping
+//     must be detected.
+//   - Functions that return float64 (Mean, Median, Percentile, Quantiles,
+//     Variance, StdDev and their sample variants) convert every element to
+//     float64 first, so integer inputs cannot overflow, but integers beyond
+//     2^53 lose precision.
+//   - If the input contains a NaN, the float64 results are NaN. MinMax also
+//     returns NaN for both bounds, matching the built-in min and max.
+//   - Empty input is reported with a false ok result rather than a NaN.
+
+// ErrOverflow is returned by SumChecked and ProductChecked when the result
+// does not fit in the element type.
+var ErrOverflow = errors.New("functional: integer overflow")
+
+// Sum returns the sum of the elements of a slice.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to sum. Can be nil or empty.
+//
+// Returns:
+//
+//	N: The sum of all elements, or 0 for nil or empty input. Integer sums wrap
+//	   on overflow; use SumChecked to detect it.
+func Sum[N Number](input []N) N {
+	var total N
+	for _, v := range input {
+		total += v
+	}
+	return total
+}
+
+// SumOf returns the sum of a numeric projection of the elements of a slice.
+// It is the ungrouped counterpart of SumBy.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the input slice.
+//	N: The numeric type being summed.
+//
+// Parameters:
+//
+//	input:     The slice to sum. Can be nil or empty.
+//	valueFunc: A function that returns the number to add for an element.
+//
+// Returns:
+//
+//	N: The sum of valueFunc over all elements, or 0 for nil or empty input.
+//	   Integer sums wrap on overflow.
+func SumOf[T any, N Number](input []T, valueFunc func(element T) N) N {
+	var total N
+	for _, item := range input {
+		total += valueFunc(item)
+	}
+	return total
+}
+
+// SumChecked returns the sum of the elements of an integer slice, or
+// ErrOverflow if any partial sum wraps around.
+//
+// Type Parameters:
+//
+//	N: The integer type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to sum. Can be nil or empty.
+//
+// Returns:
+//
+//	N:     The sum, or the partial sum before the overflowing element.
+//	error: ErrOverflow if the sum overflowed, nil otherwise.
+func SumChecked[N Integer](input []N) (N, error) {
+	var total N
+	for _, v := range input {
+		next := total + v
+		if (v > 0 && next < total) || (v < 0 && next > total) {
+			return total, ErrOverflow
+		}
+		total = next
+	}
+	return total, nil
+}
+
+// Product returns the product of the elements of a slice.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to multiply. Can be nil or empty.
+//
+// Returns:
+//
+//	N: The product of all elements, or 1 (the empty product) for nil or empty
+//	   input. Integer products wrap on overflow; use ProductChecked to detect it.
+func Product[N Number](input []N) N {
+	total := N(1)
+	for _, v := range input {
+		total *= v
+	}
+	return total
+}
+
+// ProductChecked returns the product of the elements of an integer slice, or
+// ErrOverflow if any partial product wraps around.
+//
+// Type Parameters:
+//
+//	N: The integer type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to multiply. Can be nil or empty.
+//
+// Returns:
+//
+//	N:     The product, or the partial product before the overflowing element.
+//	error: ErrOverflow if the product overflowed, nil otherwise.
+func ProductChecked[N Integer](input []N) (N, error) {
+	total := N(1)
+	for _, v := range input {
+		next, overflow := mulOverflows(total, v)
+		if overflow {
+			return total, ErrOverflow
+		}
+		total = next
+	}
+	return total, nil
+}
+
+// mulOverflows multiplies a and b and reports whether the result wrapped.
+func mulOverflows[N Integer](a, b N) (N, bool) {
+	if a == 0 || b == 0 {
+		return 0, false
+	}
+	p := a * b
+	if p/b != a {
+		return p, true
+	}
+	// MinInt * -1 wraps back to MinInt and slips through the division check.
+	var zero N
+	minusOne := zero - 1
+	if minusOne < zero { // Signed type.
+		isMin := func(x N) bool { return x < 0 && -x == x }
+		if (a == minusOne && isMin(b)) || (b == minusOne && isMin(a)) {
+			return p, true
+		}
+	}
+	return p, false
+}
+
+// Mean returns the arithmetic mean of the elements of a slice.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to average. Can be nil or empty.
+//
+// Returns:
+//
+//	float64: The mean. NaN if the input contains NaN. The sum of large finite
+//	         floats that overflows float64 falls back to a running mean, so the
+//	         result stays finite.
+//	bool:    false if the input is nil or empty.
+func Mean[N Number](input []N) (float64, bool) {
+	var stats RunningStats[N]
+	stats.AddSlice(input)
+	return stats.Mean(), stats.Count() > 0
+}
+
+// Median returns the middle value of the elements of a slice. For an even
+// number of elements it is the mean of the two middle values. It is equivalent
+// to Percentile(input, 50, InterpolateLinear).
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to examine. Can be nil or empty. It is not modified.
+//
+// Returns:
+//
+//	float64: The median, or NaN if the input contains NaN.
+//	bool:    false if the input is nil or empty.
+func Median[N Number](input []N) (float64, bool) {
+	return Percentile(input, 50, InterpolateLinear)
+}
+
+// Mode returns the most frequent elements of a slice. When several values are
+// tied for the highest count, all of them are returned.
+//
+// Type Parameters:
+//
+//	T: The type of elements in the slice, must be comparable.
+//
+// Parameters:
+//
+//	input: The slice to examine. Can be nil or empty.
+//
+// Returns:
+//
+//	[]T: The most frequent values in order of first appearance. Returns an
+//	     empty slice for nil or empty input. Because NaN is not equal to
+//	     itself, every NaN is counted as a distinct value occurring once, so
+//	     NaNs are only returned when no value occurs more than once.
+func Mode[T comparable](input []T) []T {
+	counts := make(map[T]int, len(input))
+	best := 0
+	for _, v := range input {
+		if v != v { // NaN: a distinct value that occurs once.
+			best = max(best, 1)
+			continue
+		}
+		counts[v]++
+		best = max(best, counts[v])
+	}
+
+	result := make([]T, 0)
+	for _, v := range input {
+		if v != v {
+			if best == 1 {
+				result = append(result, v)
+			}
+			continue
+		}
+		if counts[v] == best {
+			result = append(result, v)
+			counts[v] = 0 // Emit each mode once.
+		}
+	}
+	return result
+}
+
+// Variance returns the population variance of the elements of a slice,
+// computed with Welford's algorithm for numerical stability.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to examine. Can be nil or empty.
+//
+// Returns:
+//
+//	float64: The population variance (divisor n). NaN if the input contains
+//	         NaN or an infinity.
+//	bool:    false if the input is nil or empty.
+func Variance[N Number](input []N) (float64, bool) {
+	var stats RunningStats[N]
+	stats.AddSlice(input)
+	return stats.Variance(), stats.Count() > 0
+}
+
+// SampleVariance returns the sample variance (divisor n-1) of the elements of
+// a slice. It reports false for fewer than two elements.
+func SampleVariance[N Number](input []N) (float64, bool) {
+	var stats RunningStats[N]
+	stats.AddSlice(input)
+	return stats.SampleVariance(), stats.Count() > 1
+}
+
+// StdDev returns the population standard deviation of the elements of a slice,
+// the square root of Variance. It reports false for nil or empty input.
+func StdDev[N Number](input []N) (float64, bool) {
+	v, ok := Variance(input)
+	return math.Sqrt(v), ok
+}
+
+// SampleStdDev returns the sample standard deviation of the elements of a
+// slice, the square root of SampleVariance. It reports false for fewer than
+// two elements.
+func SampleStdDev[N Number](input []N) (float64, bool) {
+	v, ok := SampleVariance(input)
+	return math.Sqrt(v), ok
+}
+
+// Interpolation selects how Percentile and Quantiles pick a value when the
+// requested rank falls between two elements i < j of the sorted input.
+type Interpolation int
+
+const (
+	// InterpolateLinear returns x[i] + (x[j]-x[i]) * fraction.
+	InterpolateLinear Interpolation = iota
+	// InterpolateLower returns x[i].
+	InterpolateLower
+	// InterpolateHigher returns x[j].
+	InterpolateHigher
+	// InterpolateNearest returns whichever of x[i] and x[j] is closest, with
+	// ties going to the even index.
+	InterpolateNearest
+	// InterpolateMidpoint returns (x[i] + x[j]) / 2.
+	InterpolateMidpoint
+)
+
+// Percentile returns the p-th percentile of the elements of a slice.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input:  The slice to examine. Can be nil or empty. It is not modified.
+//	p:      The percentile to compute, between 0 and 100 inclusive.
+//	method: How to interpolate between neighbouring elements.
+//
+// Returns:
+//
+//	float64: The percentile, or NaN if the input contains NaN.
+//	bool:    false if the input is nil or empty.
+//
+// Panics if p is outside [0, 100] or method is unknown.
+func Percentile[N Number](input []N, p float64, method Interpolation) (float64, bool) {
+	if !(p >= 0 && p <= 100) { // Also rejects NaN.
+		panic("functional.Percentile: p must be between 0 and 100")
+	}
+	result, ok := Quantiles(input, []float64{p / 100}, method)
+	if !ok {
+		return 0, false
+	}
+	return result[0], true
+}
+
+// Quantiles returns several quantiles of the elements of a slice, sorting the
+// input only once.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input:  The slice to examine. Can be nil or empty. It is not modified.
+//	qs:     The quantiles to compute, each between 0 and 1 inclusive.
+//	method: How to interpolate between neighbouring elements.
+//
+// Returns:
+//
+//	[]float64: One result per entry of qs, in the same order. Every result is
+//	           NaN if the input contains NaN.
+//	bool:      false if the input is nil or empty (the slice is then nil).
+//
+// Panics if any q is outside [0, 1] or method is unknown.
+func Quantiles[N Number](input []N, qs []float64, method Interpolation) ([]float64, bool) {
+	for _, q := range qs {
+		if !(q >= 0 && q <= 1) {
+			panic("functional.Quantiles: quantiles must be between 0 and 1")
+		}
+	}
+	if method < InterpolateLinear || method > InterpolateMidpoint {
+		panic("functional.Quantiles: unknown interpolation method")
+	}
+	if len(input) == 0 {
+		return nil, false
+	}
+
+	sorted, hasNaN := sortedFloats(input)
+	result := make([]float64, len(qs))
+	for i, q := range qs {
+		if hasNaN {
+			result[i] = math.NaN()
+			continue
+		}
+		result[i] = quantileSorted(sorted, q, method)
+	}
+	return result, true
+}
+
+// sortedFloats returns a sorted float64 copy of input and whether it holds a NaN.
+func sortedFloats[N Number](input []N) ([]float64, bool) {
+	sorted := make([]float64, len(input))
+	hasNaN := false
+	for i, v := range input {
+		sorted[i] = float64(v)
+		hasNaN = hasNaN || math.IsNaN(sorted[i])
+	}
+	if !hasNaN {
+		slices.Sort(sorted)
+	}
+	return sorted, hasNaN
+}
+
+// quantileSorted computes quantile q of a sorted, NaN-free, non-empty slice.
+func quantileSorted(sorted []float64, q float64, method Interpolation) float64 {
+	rank := q * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	frac := rank - float64(lo)
+	if lo == hi {
+		return sorted[lo]
+	}
+
+	switch method {
+	case InterpolateLower:
+		return sorted[lo]
+	case InterpolateHigher:
+		return sorted[hi]
+	case InterpolateNearest:
+		return sorted[int(math.RoundToEven(rank))]
+	case InterpolateMidpoint:
+		return (sorted[lo] + sorted[hi]) / 2
+	default: // InterpolateLinear
+		return sorted[lo] + (sorted[hi]-sorted[lo])*frac
+	}
+}
+
+// MinMax returns the smallest and largest elements of a slice in one pass.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to examine. Can be nil or empty.
+//
+// Returns:
+//
+//	minimum, maximum: The bounds of the input. Both are NaN if the input
+//	                  contains NaN.
+//	ok:               false if the input is nil or empty.
+func MinMax[N Number](input []N) (minimum, maximum N, ok bool) {
+	if len(input) == 0 {
+		return minimum, maximum, false
+	}
+	minimum, maximum = input[0], input[0]
+	for _, v := range input[1:] {
+		minimum = min(minimum, v)
+		maximum = max(maximum, v)
+	}
+	return minimum, maximum, true
+}
+
+// HistogramBin is one bucket of a Histogram. It covers [Lower, Upper), except
+// for the last bin which also includes Upper.
+type HistogramBin struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+// Histogram counts the elements of a slice into equal-width bins spanning the
+// range from the smallest to the largest element.
+//
+// Type Parameters:
+//
+//	N: The numeric type of the elements.
+//
+// Parameters:
+//
+//	input: The slice to count. Can be nil or empty.
+//	bins:  The number of bins. Must be positive.
+//
+// Returns:
+//
+//	[]HistogramBin: The bins in ascending order. NaN and infinite values are
+//	                not counted. If every finite value is equal, the bins span
+//	                [v-0.5, v+0.5]. Ranges wider than math.MaxFloat64 are
+//	                handled without overflow. Returns an empty slice if there
+//	                are no finite values.
+//
+// Panics if bins is not positive.
+func Histogram[N Number](input []N, bins int) []HistogramBin {
+	if bins <= 0 {
+		panic("functional.Histogram: bins must be positive")
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range input {
+		f := float64(v)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		lo, hi = min(lo, f), max(hi, f)
+	}
+	if lo > hi { // No finite values.
+		return []HistogramBin{}
+	}
+	if lo == hi {
+		lo, hi = lo-0.5, hi+0.5
+	}
+
+	// If the range is wider than the largest float64, as from -MaxFloat64 to
+	// MaxFloat64, work in halved units so that no edge overflows.
+	scale := 1.0
+	if math.IsInf(hi-lo, 0) {
+		scale = 0.5
+	}
+	base := lo * scale
+	width := (hi*scale - base) / float64(bins)
+	result := make([]HistogramBin, bins)
+	for i := range result {
+		result[i].Lower = (base + float64(i)*width) / scale
+		result[i].Upper = (base + float64(i+1)*width) / scale
+	}
+	result[bins-1].Upper = hi // Avoid rounding drift on the closing edge.
+
+	for _, v := range input {
+		f := float64(v)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		// Clamp to guard against the top value and rounding at bin edges.
+		idx := min(max(int((f*scale-base)/width), 0), bins-1)
+		result[idx].Count++
+	}
+	return result
+}
+
+// RunningStats is a streaming accumulator for count, mean, variance and range,
+// based on Welford's online algorithm. It needs O(1) memory, so it works with
+// iterators and channels where the input cannot be held in a slice. The zero
+// value is an empty accumulator ready to use.
+//
+// RunningStats is not safe for concurrent use; give each goroutine its own
+// accumulator and combine them with Merge.
+type RunningStats[N Number] struct {
+	count    int
+	mean     float64 // Welford running mean.
+	m2       float64 // Sum of squared differences from the mean.
+	sum      float64
+	infinite bool // Whether an infinite value was added.
+	min, max N
+}
+
+// Add records one value.
+func (r *RunningStats[N]) Add(x N) {
+	f := float64(x)
+	r.count++
+	if r.count == 1 {
+		r.min, r.max = x, x
+	} else {
+		r.min, r.max = min(r.min, x), max(r.max, x)
+	}
+	r.sum += f
+	r.infinite = r.infinite || math.IsInf(f, 0)
+	delta := f - r.mean
+	r.mean += delta / float64(r.count)
+	r.m2 += delta * (f - r.mean)
+}
+
+// AddSlice records every element of a slice.
+func (r *RunningStats[N]) AddSlice(input []N) {
+	for _, v := range input {
+		r.Add(v)
+	}
+}
+
+// AddSeq records every value yielded by an iterator.
+func (r *RunningStats[N]) AddSeq(seq iter.Seq[N]) {
+	for v := range seq {
+		r.Add(v)
+	}
+}
+
+// Merge folds the values recorded by other into r, as if they had been added
+// to r directly. It uses the parallel variant of Welford's algorithm, so
+// partial accumulators built on separate goroutines can be combined.
+func (r *RunningStats[N]) Merge(other RunningStats[N]) {
+	if other.count == 0 {
+		return
+	}
+	if r.count == 0 {
+		*r = other
+		return
+	}
+	n := float64(r.count + other.count)
+	delta := other.mean - r.mean
+	r.mean += delta * float64(other.count) / n
+	r.m2 += other.m2 + delta*delta*float64(r.count)*float64(other.count)/n
+	r.count += other.count
+	r.sum += other.sum
+	r.infinite = r.infinite || other.infinite
+	r.min, r.max = min(r.min, other.min), max(r.max, other.max)
+}
+
+// Count returns the number of values recorded.
+func (r *RunningStats[N]) Count() int {
+	return r.count
+}
+
+// Sum returns the sum of the values recorded, accumulated as float64.
+func (r *RunningStats[N]) Sum() float64 {
+	return r.sum
+}
+
+// Mean returns the mean of the values recorded, or NaN if there are none.
+func (r *RunningStats[N]) Mean() float64 {
+	if r.count == 0 {
+		return math.NaN()
+	}
+	// The plain sum is exact enough for the mean and handles infinities
+	// correctly; only fall back to the running mean when finite values
+	// overflowed the sum.
+	if !math.IsInf(r.sum, 0) || r.infinite {
+		return r.sum / float64(r.count)
+	}
+	return r.mean
+}
+
+// Variance returns the population variance of the values recorded, or NaN if
+// there are none.
+func (r *RunningStats[N]) Variance() float64 {
+	if r.count == 0 {
+		return math.NaN()
+	}
+	return r.m2 / float64(r.count)
+}
+
+// SampleVariance returns the sample variance of the values recorded, or NaN if
+// there are fewer than two.
+func (r *RunningStats[N]) SampleVariance() float64 {
+	if r.count < 2 {
+		return math.NaN()
+	}
+	return r.m2 / float64(r.count-1)
+}
+
+// StdDev returns the population standard deviation of the values recorded, or
+// NaN if there are none.
+func (r *RunningStats[N]) StdDev() float64 {
+	return math.Sqrt(r.Variance())
+}
+
+// Min returns the smallest value recorded. The boolean is false if no values
+// have been recorded.
+func (r *RunningStats[N]) Min() (N, bool) {
+	return r.min, r.count > 0
+}
+
+// Max returns the largest value recorded. The boolean is false if no values
+// have been recorded.
+func (r *RunningStats[N]) Max() (N, bool) {
+	return r.max, r.count > 0
+}
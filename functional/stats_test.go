@@ -0,0 +1,452 @@
+package functional_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/JackovAlltrades/go-generics/functional"
+)
+
+// floatsClose reports whether two floats are equal within a small tolerance,
+// treating two NaNs as equal.
+func floatsClose(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	if a == b { // Covers matching infinities.
+		return true
+	}
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+type celsius float64
+
+func TestSumAndProduct(t *testing.T) {
+	if got := functional.Sum([]int{1, 2, 3, 4}); got != 10 {
+		t.Errorf("Sum() = %d, want 10", got)
+	}
+	if got := functional.Sum([]celsius{1.5, 2.5}); got != 4 {
+		t.Errorf("Sum() named float type = %v, want 4", got)
+	}
+	if got := functional.Sum([]int(nil)); got != 0 {
+		t.Errorf("Sum(nil) = %d, want 0", got)
+	}
+	if got := functional.Sum([]int8{100, 100}); got != -56 {
+		t.Errorf("Sum() int8 overflow = %d, want wrapped -56", got)
+	}
+
+	if got := functional.SumOf([]person{{"A", 20}, {"B", 22}}, func(p person) int { return p.Age }); got != 42 {
+		t.Errorf("SumOf() = %d, want 42", got)
+	}
+
+	if got := functional.Product([]int{2, 3, 4}); got != 24 {
+		t.Errorf("Product() = %d, want 24", got)
+	}
+	if got := functional.Product([]float64{}); got != 1 {
+		t.Errorf("Product(empty) = %v, want 1", got)
+	}
+}
+
+func TestSumChecked(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   []int8
+		want    int8
+		wantErr error
+	}{
+		{"NoOverflow", []int8{100, 27}, 127, nil},
+		{"PositiveOverflow", []int8{100, 27, 1}, 127, functional.ErrOverflow},
+		{"NegativeOverflow", []int8{-100, -28, -1}, -128, functional.ErrOverflow},
+		{"MixedSigns", []int8{127, -1, 1}, 127, nil},
+		{"Nil", nil, 0, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := functional.SumChecked(tc.input)
+			if got != tc.want || !errors.Is(err, tc.wantErr) {
+				t.Errorf("SumChecked() = %d, %v; want %d, %v", got, err, tc.want, tc.wantErr)
+			}
+		})
+	}
+
+	if got, err := functional.SumChecked([]uint8{200, 55, 1}); !errors.Is(err, functional.ErrOverflow) || got != 255 {
+		t.Errorf("SumChecked() uint8 = %d, %v; want 255, ErrOverflow", got, err)
+	}
+}
+
+func TestProductChecked(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   []int8
+		want    int8
+		wantErr error
+	}{
+		{"NoOverflow", []int8{-2, 4, 8}, -64, nil},
+		{"Overflow", []int8{16, 8, 2}, 16, functional.ErrOverflow},
+		{"MinTimesMinusOne", []int8{-128, -1}, -128, functional.ErrOverflow},
+		{"MinusOneTimesMin", []int8{-1, -128}, -1, functional.ErrOverflow},
+		{"Zero", []int8{100, 0, 100}, 0, nil},
+		{"Empty", []int8{}, 1, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := functional.ProductChecked(tc.input)
+			if got != tc.want || !errors.Is(err, tc.wantErr) {
+				t.Errorf("ProductChecked() = %d, %v; want %d, %v", got, err, tc.want, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMeanMedianVariance(t *testing.T) {
+	nan := math.NaN()
+	testCases := []struct {
+		name         string
+		input        []float64
+		wantMean     float64
+		wantMedian   float64
+		wantVariance float64
+		wantSample   float64
+	}{
+		{"Odd", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, 4.5, 4, 32.0 / 7},
+		{"Single", []float64{3}, 3, 3, 0, nan},
+		{"Unsorted", []float64{9, 1, 5}, 5, 5, 32.0 / 3, 16},
+		{"WithNaN", []float64{1, nan, 3}, nan, nan, nan, nan},
+		{"WithInf", []float64{1, math.Inf(1)}, math.Inf(1), math.Inf(1), nan, nan},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, ok := functional.Mean(tc.input); !ok || !floatsClose(got, tc.wantMean) {
+				t.Errorf("Mean() = %v, %v; want %v", got, ok, tc.wantMean)
+			}
+			if got, ok := functional.Median(tc.input); !ok || !floatsClose(got, tc.wantMedian) {
+				t.Errorf("Median() = %v, %v; want %v", got, ok, tc.wantMedian)
+			}
+			if got, ok := functional.Variance(tc.input); !ok || !floatsClose(got, tc.wantVariance) {
+				t.Errorf("Variance() = %v, %v; want %v", got, ok, tc.wantVariance)
+			}
+			if got, _ := functional.SampleVariance(tc.input); !floatsClose(got, tc.wantSample) {
+				t.Errorf("SampleVariance() = %v; want %v", got, tc.wantSample)
+			}
+			if got, _ := functional.StdDev(tc.input); !floatsClose(got, math.Sqrt(tc.wantVariance)) {
+				t.Errorf("StdDev() = %v; want %v", got, math.Sqrt(tc.wantVariance))
+			}
+		})
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		if _, ok := functional.Mean([]int{}); ok {
+			t.Error("Mean(empty) reported ok")
+		}
+		if _, ok := functional.Median([]int(nil)); ok {
+			t.Error("Median(nil) reported ok")
+		}
+		if _, ok := functional.Variance([]int{}); ok {
+			t.Error("Variance(empty) reported ok")
+		}
+		if _, ok := functional.SampleStdDev([]int{1}); ok {
+			t.Error("SampleStdDev() with one element reported ok")
+		}
+	})
+
+	t.Run("IntegersDoNotOverflow", func(t *testing.T) {
+		got, _ := functional.Mean([]int8{127, 127, 127})
+		if got != 127 {
+			t.Errorf("Mean() int8 = %v, want 127", got)
+		}
+	})
+
+	t.Run("LargeFloatsDoNotOverflow", func(t *testing.T) {
+		got, _ := functional.Mean([]float64{math.MaxFloat64, math.MaxFloat64})
+		if !floatsClose(got, math.MaxFloat64) {
+			t.Errorf("Mean() of huge floats = %v, want MaxFloat64", got)
+		}
+	})
+
+	t.Run("MedianDoesNotModifyInput", func(t *testing.T) {
+		input := []int{3, 1, 2}
+		functional.Median(input)
+		if !reflect.DeepEqual(input, []int{3, 1, 2}) {
+			t.Errorf("Median() modified its input: %v", input)
+		}
+	})
+}
+
+func TestMode(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"SingleMode", []int{1, 2, 2, 3}, []int{2}},
+		{"TiedModesInFirstAppearanceOrder", []int{3, 1, 3, 1, 2}, []int{3, 1}},
+		{"AllUnique", []int{5, 4}, []int{5, 4}},
+		{"Nil", nil, []int{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := functional.Mode(tc.input); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Mode() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMode_NaN(t *testing.T) {
+	nan := math.NaN()
+	testCases := []struct {
+		name  string
+		input []float64
+		want  string // fmt.Sprint of the result, since NaN != NaN
+	}{
+		{"NaNIsDistinctFromOtherValues", []float64{1, nan}, "[1 NaN]"},
+		{"EachNaNIsDistinct", []float64{nan, nan}, "[NaN NaN]"},
+		{"RepeatedValueBeatsNaNs", []float64{nan, 2, nan, 2}, "[2]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := fmt.Sprint(functional.Mode(tc.input)); got != tc.want {
+				t.Errorf("Mode() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPercentileAndQuantiles(t *testing.T) {
+	input := []int{1, 2, 3, 4}
+	testCases := []struct {
+		method functional.Interpolation
+		p      float64
+		want   float64
+	}{
+		{functional.InterpolateLinear, 40, 2.2},
+		{functional.InterpolateLower, 40, 2},
+		{functional.InterpolateHigher, 40, 3},
+		{functional.InterpolateNearest, 40, 2},
+		{functional.InterpolateNearest, 50, 3}, // Rank 1.5 rounds half to even, giving index 2.
+		{functional.InterpolateMidpoint, 40, 2.5},
+		{functional.InterpolateLinear, 0, 1},
+		{functional.InterpolateLinear, 100, 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("method%d_p%v", tc.method, tc.p), func(t *testing.T) {
+			got, ok := functional.Percentile(input, tc.p, tc.method)
+			if !ok || !floatsClose(got, tc.want) {
+				t.Errorf("Percentile(%v, %v) = %v, %v; want %v", tc.p, tc.method, got, ok, tc.want)
+			}
+		})
+	}
+
+	t.Run("Quantiles", func(t *testing.T) {
+		got, ok := functional.Quantiles([]float64{10, 40, 20, 30, 50}, []float64{0.25, 0.5, 0.75}, functional.InterpolateLinear)
+		if !ok || !reflect.DeepEqual(got, []float64{20, 30, 40}) {
+			t.Errorf("Quantiles() = %v, %v; want [20 30 40]", got, ok)
+		}
+		if got, ok := functional.Quantiles([]int{}, []float64{0.5}, functional.InterpolateLinear); ok || got != nil {
+			t.Errorf("Quantiles(empty) = %v, %v; want nil, false", got, ok)
+		}
+	})
+
+	t.Run("InvalidArgumentsPanic", func(t *testing.T) {
+		for name, call := range map[string]func(){
+			"PercentileAbove100": func() { functional.Percentile(input, 101, functional.InterpolateLinear) },
+			"PercentileNaN":      func() { functional.Percentile(input, math.NaN(), functional.InterpolateLinear) },
+			"QuantileNegative":   func() { functional.Quantiles(input, []float64{-0.1}, functional.InterpolateLinear) },
+			"UnknownMethod":      func() { functional.Quantiles(input, []float64{0.5}, functional.Interpolation(99)) },
+		} {
+			t.Run(name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s did not panic", name)
+					}
+				}()
+				call()
+			})
+		}
+	})
+}
+
+func TestMinMax(t *testing.T) {
+	if lo, hi, ok := functional.MinMax([]int{3, -1, 7, 2}); !ok || lo != -1 || hi != 7 {
+		t.Errorf("MinMax() = %d, %d, %v; want -1, 7, true", lo, hi, ok)
+	}
+	if _, _, ok := functional.MinMax([]int(nil)); ok {
+		t.Error("MinMax(nil) reported ok")
+	}
+	if lo, hi, _ := functional.MinMax([]float64{1, math.NaN(), 3}); !math.IsNaN(lo) || !math.IsNaN(hi) {
+		t.Errorf("MinMax() with NaN = %v, %v; want NaN, NaN", lo, hi)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	t.Run("EqualWidthBins", func(t *testing.T) {
+		got := functional.Histogram([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 10, math.NaN(), math.Inf(1)}, 5)
+		wantCounts := []int{2, 2, 2, 2, 2}
+		for i, bin := range got {
+			if bin.Count != wantCounts[i] {
+				t.Errorf("Histogram() bin %d = %+v, want count %d", i, bin, wantCounts[i])
+			}
+		}
+		if got[0].Lower != 0 || got[4].Upper != 10 || got[1].Lower != 2 {
+			t.Errorf("Histogram() edges = %+v, want [0,2) ... [8,10]", got)
+		}
+	})
+
+	t.Run("AllEqual", func(t *testing.T) {
+		got := functional.Histogram([]int{4, 4, 4}, 2)
+		if len(got) != 2 || got[0].Lower != 3.5 || got[1].Upper != 4.5 || got[0].Count+got[1].Count != 3 {
+			t.Errorf("Histogram() of equal values = %+v", got)
+		}
+	})
+
+	t.Run("RangeWiderThanMaxFloat64", func(t *testing.T) {
+		got := functional.Histogram([]float64{-math.MaxFloat64, 0, math.MaxFloat64}, 4)
+		half := math.MaxFloat64 / 2
+		wantEdges := []float64{-math.MaxFloat64, -half, 0, half, math.MaxFloat64}
+		wantCounts := []int{1, 0, 1, 1}
+		if len(got) != 4 {
+			t.Fatalf("Histogram() = %+v, want 4 bins", got)
+		}
+		for i, bin := range got {
+			if bin.Count != wantCounts[i] {
+				t.Errorf("bin %d count = %d, want %d", i, bin.Count, wantCounts[i])
+			}
+			for _, edge := range [][2]float64{{bin.Lower, wantEdges[i]}, {bin.Upper, wantEdges[i+1]}} {
+				if math.IsInf(edge[0], 0) || math.IsNaN(edge[0]) || math.Abs(edge[0]-edge[1]) > 1e-15*math.MaxFloat64 {
+					t.Errorf("bin %d = %+v, want edges [%g, %g]", i, bin, wantEdges[i], wantEdges[i+1])
+				}
+			}
+		}
+	})
+
+	t.Run("NoFiniteValues", func(t *testing.T) {
+		if got := functional.Histogram([]float64{math.NaN()}, 3); got == nil || len(got) != 0 {
+			t.Errorf("Histogram() without finite values = %#v, want empty non-nil slice", got)
+		}
+	})
+
+	t.Run("NonPositiveBinsPanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Histogram() with 0 bins did not panic")
+			}
+		}()
+		functional.Histogram([]int{1}, 0)
+	})
+}
+
+func TestRunningStats(t *testing.T) {
+	data := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	var whole functional.RunningStats[float64]
+	whole.AddSeq(slices.Values(data))
+	if whole.Count() != 8 || whole.Mean() != 5 || whole.Variance() != 4 || whole.StdDev() != 2 {
+		t.Errorf("RunningStats = count %d mean %v variance %v; want 8, 5, 4",
+			whole.Count(), whole.Mean(), whole.Variance())
+	}
+	if lo, ok := whole.Min(); !ok || lo != 2 {
+		t.Errorf("Min() = %v, %v; want 2, true", lo, ok)
+	}
+	if hi, ok := whole.Max(); !ok || hi != 9 {
+		t.Errorf("Max() = %v, %v; want 9, true", hi, ok)
+	}
+
+	t.Run("Merge", func(t *testing.T) {
+		var left, right, empty functional.RunningStats[float64]
+		left.AddSlice(data[:3])
+		right.AddSlice(data[3:])
+		left.Merge(right)
+		left.Merge(empty)
+		if left.Count() != whole.Count() || !floatsClose(left.Mean(), whole.Mean()) ||
+			!floatsClose(left.SampleVariance(), whole.SampleVariance()) {
+			t.Errorf("merged stats = n %d mean %v var %v; want n %d mean %v var %v",
+				left.Count(), left.Mean(), left.SampleVariance(),
+				whole.Count(), whole.Mean(), whole.SampleVariance())
+		}
+		if lo, _ := left.Min(); lo != 2 {
+			t.Errorf("merged Min() = %v, want 2", lo)
+		}
+
+		empty.Merge(right)
+		if empty.Count() != right.Count() || empty.Mean() != right.Mean() {
+			t.Errorf("merge into empty = n %d mean %v; want n %d mean %v",
+				empty.Count(), empty.Mean(), right.Count(), right.Mean())
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var r functional.RunningStats[int]
+		if !math.IsNaN(r.Mean()) || !math.IsNaN(r.Variance()) || !math.IsNaN(r.SampleVariance()) {
+			t.Error("empty RunningStats should report NaN statistics")
+		}
+		if _, ok := r.Min(); ok {
+			t.Error("empty RunningStats Min() reported ok")
+		}
+	})
+
+	t.Run("NumericallyStable", func(t *testing.T) {
+		var r functional.RunningStats[float64]
+		for _, v := range []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16} {
+			r.Add(v)
+		}
+		if !floatsClose(r.SampleVariance(), 30) {
+			t.Errorf("SampleVariance() with large offset = %v, want 30", r.SampleVariance())
+		}
+	})
+}
+
+func ExampleQuantiles() {
+	latenciesMs := []int{12, 15, 11, 90, 14, 13, 16, 200, 12, 15}
+	q, _ := functional.Quantiles(latenciesMs, []float64{0.5, 0.9, 0.99}, functional.InterpolateLinear)
+	fmt.Printf("p50=%.1f p90=%.1f p99=%.1f\n", q[0], q[1], q[2])
+	// Output:
+	// p50=14.5 p90=101.0 p99=190.1
+}
+
+func ExampleRunningStats() {
+	var stats functional.RunningStats[int]
+	stats.AddSeq(slices.Values([]int{2, 4, 4, 4, 5, 5, 7, 9}))
+
+	fmt.Println("count:", stats.Count())
+	fmt.Println("mean:", stats.Mean())
+	fmt.Println("stddev:", stats.StdDev())
+	// Output:
+	// count: 8
+	// mean: 5
+	// stddev: 2
+}
+
+func BenchmarkMean_Generic_N10000(b *testing.B) {
+	data := make([]float64, 10000)
+	for i := range data {
+		data[i] = float64(i % 97)
+	}
+	b.ResetTimer()
+	var result float64
+	for i := 0; i < b.N; i++ {
+		result, _ = functional.Mean(data)
+	}
+	_ = result
+}
+
+func BenchmarkMean_Loop_N10000(b *testing.B) {
+	data := make([]float64, 10000)
+	for i := range data {
+		data[i] = float64(i % 97)
+	}
+	b.ResetTimer()
+	var result float64
+	for i := 0; i < b.N; i++ {
+		sum := 0.0
+		for _, v := range data {
+			sum += v
+		}
+		result = sum / float64(len(data))
+	}
+	_ = result
+}